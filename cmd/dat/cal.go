@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Setheck/dat/pkg/dat"
+)
+
+// CalCommand renders a month calendar around a time
+type CalCommand struct {
+	cmd *cobra.Command
+
+	milliseconds *bool
+	tf           *bool
+	zone         *string
+	weekNumbers  *bool
+	firstWeekday *string
+	color        *string
+}
+
+// NewCalCommand creates a new instance of a CalCommand
+func NewCalCommand() *CalCommand {
+	cc := &CalCommand{}
+	cc.cmd = &cobra.Command{
+		Use:   "cal [epoch]",
+		Short: "display a month calendar around the epoch",
+		Long: `cal displays the month of the epoch like cal(1), highlighting its day.
+When an epoch is not given the current time is used.`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cc.RunE(args)
+		},
+	}
+	return cc
+}
+
+// ParseFlags parse and assign flags
+func (c *CalCommand) ParseFlags() {
+	flgs := c.cmd.Flags()
+	c.milliseconds = flgs.BoolP("milliseconds", "m", false, "epochs in milliseconds")
+	c.tf = flgs.BoolP("tf", "t", false, "attempt to parse input as a known time format")
+	c.zone = flgs.StringP("zone", "z", "", "zone whose days are shown, a tz database name, offset such as +05:30 or POSIX TZ string, local by default")
+	c.weekNumbers = flgs.BoolP("week-numbers", "w", false, "display ISO week numbers")
+	c.firstWeekday = flgs.String("first-weekday", "sunday", "weekday the weeks start on")
+	c.color = flgs.String("color", "auto", "highlight the day, auto, always or never")
+	_ = c.cmd.RegisterFlagCompletionFunc("zone", completeZone)
+}
+
+// RunE renders the calendar
+func (c *CalCommand) RunE(args []string) error {
+	first, err := dat.ParseWeekday(*c.firstWeekday)
+	if err != nil {
+		return err
+	}
+	highlight, err := useColor(*c.color, stdOut)
+	if err != nil {
+		return err
+	}
+	loc := time.Local
+	if *c.zone != "" {
+		if loc, err = dat.LoadZone(*c.zone); err != nil {
+			return err
+		}
+	}
+
+	tm := timeNow()
+	if len(args) > 0 {
+		precision := dat.Seconds
+		if *c.milliseconds {
+			precision = dat.Milliseconds
+		}
+		conv := dat.NewConverter(dat.WithPrecision(precision), dat.WithTimeFormats(*c.tf))
+		parsed, err := conv.Parse(args[0])
+		if err != nil {
+			return err
+		}
+		tm = parsed.Time
+	}
+
+	_, err = fmt.Fprint(stdOut, RenderCalendar(tm.In(loc), first, *c.weekNumbers, highlight))
+	return err
+}
+
+// useColor resolves a color mode, auto colors terminals
+func useColor(mode string, w io.Writer) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		f, ok := w.(*os.File)
+		if !ok {
+			return false, nil
+		}
+		info, err := f.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	}
+	return false, fmt.Errorf("unknown color mode %q, must be auto, always or never", mode)
+}
+
+// RenderCalendar renders the month of tm, in its location, with weeks starting on first.
+// Week numbers are the ISO week of each row's thursday.
+func RenderCalendar(tm time.Time, first time.Weekday, weekNumbers, highlight bool) string {
+	year, month, day := tm.Date()
+	// calendar arithmetic in UTC, days are unaffected by daylight saving
+	start := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+
+	margin := ""
+	if weekNumbers {
+		margin = "   "
+	}
+	const width = 7*3 - 1
+	title := fmt.Sprintf("%s %d", month, year)
+	output := fmt.Sprintln(margin + strings.Repeat(" ", (width-len(title))/2) + title)
+
+	names := make([]string, 7)
+	for i := range names {
+		names[i] = time.Weekday((int(first) + i) % 7).String()[:2]
+	}
+	output += fmt.Sprintln(margin + strings.Join(names, " "))
+
+	offset := (int(start.Weekday()) - int(first) + 7) % 7
+	thursday := (int(time.Thursday) - int(first) + 7) % 7
+	for row := start.AddDate(0, 0, -offset); row.Month() == month || row.Before(start); row = row.AddDate(0, 0, 7) {
+		line := ""
+		if weekNumbers {
+			_, week := row.AddDate(0, 0, thursday).ISOWeek()
+			line = fmt.Sprintf("%2d ", week)
+		}
+		cells := make([]string, 7)
+		for i := range cells {
+			d := row.AddDate(0, 0, i)
+			switch {
+			case d.Month() != month:
+				cells[i] = "  "
+			case highlight && d.Day() == day:
+				cells[i] = fmt.Sprintf("\x1b[7m%2d\x1b[0m", d.Day())
+			default:
+				cells[i] = fmt.Sprintf("%2d", d.Day())
+			}
+		}
+		output += fmt.Sprintln(strings.TrimRight(line+strings.Join(cells, " "), " "))
+	}
+	return output
+}
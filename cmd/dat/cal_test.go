@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalCommand_ParseFlags(t *testing.T) {
+	cc := NewCalCommand()
+	cc.ParseFlags()
+
+	fset := cc.cmd.Flags()
+	for _, name := range []string{"milliseconds", "tf", "zone", "week-numbers", "first-weekday", "color"} {
+		assert.NotNil(t, fset.Lookup(name), name)
+	}
+	assert.Equal(t, "sunday", *cc.firstWeekday)
+	assert.Equal(t, "auto", *cc.color)
+}
+
+func TestRenderCalendar(t *testing.T) {
+	tm := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	tests := []struct {
+		name        string
+		first       time.Weekday
+		weekNumbers bool
+		highlight   bool
+		want        string
+	}{
+		{"sunday", time.Sunday, false, false, `   November 2023
+Su Mo Tu We Th Fr Sa
+          1  2  3  4
+ 5  6  7  8  9 10 11
+12 13 14 15 16 17 18
+19 20 21 22 23 24 25
+26 27 28 29 30
+`},
+		{"monday with week numbers", time.Monday, true, true, "      November 2023\n" +
+			"   Mo Tu We Th Fr Sa Su\n" +
+			"44        1  2  3  4  5\n" +
+			"45  6  7  8  9 10 11 12\n" +
+			"46 13 \x1b[7m14\x1b[0m 15 16 17 18 19\n" +
+			"47 20 21 22 23 24 25 26\n" +
+			"48 27 28 29 30\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, RenderCalendar(tm, test.first, test.weekNumbers, test.highlight))
+		})
+	}
+}
+
+func TestRenderCalendar_WeekNumbers(t *testing.T) {
+	// the row of 2023-12-26 to 2024-01-01 is mostly in week 52 of 2023
+	got := RenderCalendar(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Tuesday, true, false)
+	assert.Contains(t, got, "\n52                    1\n 1  2  3  4  5  6  7  8\n")
+}
+
+func TestCalCommand_RunE(t *testing.T) {
+	saveStdOut := stdOut
+	saveTimeNow := timeNow
+	defer func() {
+		stdOut = saveStdOut
+		timeNow = saveTimeNow
+	}()
+	timeNow = func() time.Time {
+		return time.Date(2020, 2, 29, 23, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		args  []string
+		flags []string
+		want  string
+		err   bool
+	}{
+		{"now", nil, []string{"-z", "UTC"}, "   February 2020\n", false},
+		{"zone moves the day", nil, []string{"-z", "Asia/Tokyo"}, "     March 2020\n", false},
+		{"offset moves the day", nil, []string{"-z", "+01:00"}, "     March 2020\n", false},
+		{"epoch", []string{"1700000000"}, []string{"-z", "UTC"}, "   November 2023\n", false},
+		{"milliseconds", []string{"1700000000000"}, []string{"-z", "UTC", "-m"}, "   November 2023\n", false},
+		{"time format", []string{"2021-07-04T00:00:00Z"}, []string{"-z", "UTC", "--tf"}, "     July 2021\n", false},
+		{"bad epoch", []string{"asdf"}, nil, "", true},
+		{"bad zone", nil, []string{"-z", "Mars/Olympus"}, "", true},
+		{"bad weekday", nil, []string{"--first-weekday", "x"}, "", true},
+		{"bad color", nil, []string{"--color", "sometimes"}, "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outputBuffer := new(bytes.Buffer)
+			stdOut = outputBuffer
+
+			cc := NewCalCommand()
+			cc.ParseFlags()
+			assert.NoError(t, cc.cmd.Flags().Parse(test.flags))
+			err := cc.RunE(test.args)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Contains(t, outputBuffer.String(), test.want)
+			assert.NotContains(t, outputBuffer.String(), "\x1b[")
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Setheck/dat/pkg/build"
+	"github.com/Setheck/dat/pkg/dat"
+)
+
+// CompletionCommand generates shell completion scripts
+type CompletionCommand struct {
+	cmd *cobra.Command
+
+	noDesc *bool
+}
+
+// NewCompletionCommand creates a new instance of a CompletionCommand
+func NewCompletionCommand() *CompletionCommand {
+	cc := &CompletionCommand{}
+	cc.cmd = &cobra.Command{
+		Use:   "completion bash|zsh|fish|powershell",
+		Short: "generate the autocompletion script for the specified shell",
+		Long: fmt.Sprint(`generate the autocompletion script for the specified shell, for example
+  bash:       source <(`, build.Application, ` completion bash)
+  zsh:        `, build.Application, ` completion zsh > "${fpath[1]}/_`, build.Application, `"
+  fish:       `, build.Application, ` completion fish | source
+  powershell: `, build.Application, ` completion powershell | Out-String | Invoke-Expression`),
+		Args:                  cobra.ExactValidArgs(1),
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cc.RunE(cmd.Root(), args[0])
+		},
+	}
+	return cc
+}
+
+// ParseFlags parse and assign flags
+func (c *CompletionCommand) ParseFlags() {
+	flgs := c.cmd.Flags()
+	c.noDesc = flgs.Bool("no-descriptions", false, "disable completion descriptions")
+}
+
+// RunE writes the completion script for the shell
+func (c *CompletionCommand) RunE(root *cobra.Command, shell string) error {
+	desc := !*c.noDesc
+	switch shell {
+	case "bash":
+		return root.GenBashCompletionV2(stdOut, desc)
+	case "zsh":
+		if desc {
+			return root.GenZshCompletion(stdOut)
+		}
+		return root.GenZshCompletionNoDesc(stdOut)
+	case "fish":
+		return root.GenFishCompletion(stdOut, desc)
+	case "powershell":
+		if desc {
+			return root.GenPowerShellCompletionWithDesc(stdOut)
+		}
+		return root.GenPowerShellCompletion(stdOut)
+	}
+	return fmt.Errorf("unsupported shell %q", shell)
+}
+
+// deltaUnits are the units accepted by --delta
+var deltaUnits = []string{"ns", "us", "ms", "s", "m", "h", "bd"}
+
+// registerCompletions adds dynamic completion of flag values
+func registerCompletions(cmd *cobra.Command) {
+	_ = cmd.RegisterFlagCompletionFunc("zone", completeZone)
+	_ = cmd.RegisterFlagCompletionFunc("format", completeFormat)
+	_ = cmd.RegisterFlagCompletionFunc("delta", completeDelta)
+	_ = cmd.RegisterFlagCompletionFunc("format-dialect", completeDialect)
+	_ = cmd.RegisterFlagCompletionFunc("locale", completeLocale)
+	_ = cmd.RegisterFlagCompletionFunc("calendar", completeCalendar)
+	for _, flag := range []string{"truncate", "round", "start-of", "end-of"} {
+		_ = cmd.RegisterFlagCompletionFunc(flag, completeUnit)
+	}
+}
+
+// completeLocale completes the embedded locales
+func completeLocale(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return filterPrefix(append([]string{localeEnv}, dat.Locales()...), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCalendar completes the calendars, after those already listed with commas
+func completeCalendar(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	listed := toComplete[:strings.LastIndex(toComplete, ",")+1]
+	names := []string{listed + "all"}
+	for _, c := range dat.Calendars {
+		names = append(names, listed+string(c))
+	}
+	return filterPrefix(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeDialect completes the format dialects
+func completeDialect(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	dialects := make([]string, 0, len(dat.Dialects))
+	for _, d := range dat.Dialects {
+		dialects = append(dialects, string(d))
+	}
+	return filterPrefix(dialects, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeUnit completes the units times snap to
+func completeUnit(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	units := make([]string, 0, len(dat.Units))
+	for _, u := range dat.Units {
+		units = append(units, string(u))
+	}
+	return filterPrefix(units, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeZone completes tz database names, case insensitive
+func completeZone(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return filterPrefix(dat.Zones(), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFormat completes the supported time format names
+func completeFormat(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return filterPrefix(dat.FormatNames(), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeDelta suggests units once a number is typed, examples otherwise
+func completeDelta(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	directive := cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	if toComplete == "" || strings.IndexAny(toComplete[len(toComplete)-1:], "0123456789") < 0 {
+		return []string{"+1h", "-1h", "+30m", "-24h"}, directive
+	}
+	completions := make([]string, 0, len(deltaUnits))
+	for _, unit := range deltaUnits {
+		completions = append(completions, toComplete+unit)
+	}
+	return completions, directive
+}
+
+func filterPrefix(values []string, prefix string) []string {
+	var matches []string
+	lower := strings.ToLower(prefix)
+	for _, v := range values {
+		if strings.HasPrefix(strings.ToLower(v), lower) {
+			matches = append(matches, v)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompletionCommand_RunE(t *testing.T) {
+	saveStdOut := stdOut
+	defer func() { stdOut = saveStdOut }()
+
+	rc := NewRootCommand()
+	rc.ParseFlags()
+	root := rc.cmd.(*cobra.Command)
+
+	tests := []struct {
+		shell  string
+		noDesc bool
+		want   string
+		err    bool
+	}{
+		{"bash", false, "bash completion V2 for dat", false},
+		{"zsh", false, "#compdef _dat dat", false},
+		{"zsh", true, "#compdef _dat dat", false},
+		{"fish", false, "fish completion for dat", false},
+		{"powershell", false, "powershell completion for dat", false},
+		{"tcsh", false, "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.shell, func(t *testing.T) {
+			outputBuffer := new(bytes.Buffer)
+			stdOut = outputBuffer
+
+			cc := NewCompletionCommand()
+			cc.ParseFlags()
+			*cc.noDesc = test.noDesc
+			err := cc.RunE(root, test.shell)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Contains(t, outputBuffer.String(), test.want)
+		})
+	}
+}
+
+func TestRegisterCompletions(t *testing.T) {
+	rc := NewRootCommand()
+	rc.ParseFlags()
+	root := rc.cmd.(*cobra.Command)
+
+	outputBuffer := new(bytes.Buffer)
+	root.SetOut(outputBuffer)
+	root.SetArgs([]string{cobra.ShellCompRequestCmd, "--format", "kitch"})
+	assert.NoError(t, root.Execute())
+	assert.Contains(t, outputBuffer.String(), "Kitchen\n")
+}
+
+func TestCompleteFormat(t *testing.T) {
+	got, directive := completeFormat(nil, nil, "rfc33")
+	assert.Equal(t, []string{"RFC3339", "RFC3339Nano"}, got)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+}
+
+func TestCompleteZone(t *testing.T) {
+	got, _ := completeZone(nil, nil, "america/los")
+	for _, zone := range got {
+		assert.Equal(t, tzLosAngeles, zone)
+	}
+}
+
+func TestCompleteDelta(t *testing.T) {
+	got, _ := completeDelta(nil, nil, "-5")
+	assert.Equal(t, []string{"-5ns", "-5us", "-5ms", "-5s", "-5m", "-5h", "-5bd"}, got)
+
+	got, _ = completeDelta(nil, nil, "")
+	assert.NotEmpty(t, got)
+}
+
+func TestCompleteDialect(t *testing.T) {
+	got, directive := completeDialect(nil, nil, "J")
+	assert.Equal(t, []string{"java"}, got)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+}
+
+func TestCompleteLocale(t *testing.T) {
+	got, directive := completeLocale(nil, nil, "f")
+	assert.Equal(t, []string{"fi", "fr"}, got)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+	got, _ = completeLocale(nil, nil, "en")
+	assert.Equal(t, []string{"env", "en"}, got)
+}
+
+func TestCompleteCalendar(t *testing.T) {
+	got, directive := completeCalendar(nil, nil, "h")
+	assert.Equal(t, []string{"hebrew"}, got)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	got, _ = completeCalendar(nil, nil, "hebrew,")
+	assert.Len(t, got, 8)
+	got, _ = completeCalendar(nil, nil, "hebrew,j")
+	assert.Equal(t, []string{"hebrew,japanese"}, got)
+}
+
+func TestCompleteUnit(t *testing.T) {
+	got, directive := completeUnit(nil, nil, "m")
+	assert.Equal(t, []string{"minute", "month"}, got)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Setheck/dat/pkg/dat"
+)
+
+// CronCommand prints the fire times of a cron expression
+type CronCommand struct {
+	cmd *cobra.Command
+
+	from         *string
+	count        *int
+	previous     *bool
+	milliseconds *bool
+	tf           *bool
+	format       *string
+	zone         *string
+	local        *bool
+	utc          *bool
+}
+
+// NewCronCommand creates a new instance of a CronCommand
+func NewCronCommand() *CronCommand {
+	cc := &CronCommand{}
+	cc.cmd = &cobra.Command{
+		Use:   `cron "<expr>"`,
+		Short: "print the next fire times of a cron expression",
+		Long: `cron prints the next -n fire times of a cron expression after --from, or the previous
+ones with --prev. Expressions have 5 fields (minute hour day-of-month month day-of-week),
+or 6 with a leading second, and accept names (jan, mon), ranges, steps, lists and the
+macros @yearly, @monthly, @weekly, @daily and @hourly. A CRON_TZ=Zone prefix evaluates the
+schedule in that zone, otherwise in the --zone (local by default).`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cc.RunE(args[0])
+		},
+	}
+	return cc
+}
+
+// ParseFlags parse and assign flags
+func (c *CronCommand) ParseFlags() {
+	flgs := c.cmd.Flags()
+	c.from = flgs.String("from", "now", "epoch, or time with --tf, to count fire times from")
+	c.count = flgs.IntP("count", "n", 5, "number of fire times to print")
+	c.previous = flgs.BoolP("prev", "p", false, "print the fire times before --from, most recent first")
+	c.milliseconds = flgs.BoolP("milliseconds", "m", false, "epochs in milliseconds")
+	c.tf = flgs.BoolP("tf", "t", false, "attempt to parse --from as a known time format")
+	c.format = flgs.StringP("format", "f", "", "https://golang.org/pkg/time/ format for time output including constant names")
+	c.zone = flgs.StringP("zone", "z", "", "display and evaluate in a specific time zone by tz database name, offset such as +05:30 or POSIX TZ string")
+	c.local = flgs.BoolP("local", "l", false, "display the formatted time in the local timezone")
+	c.utc = flgs.BoolP("utc", "u", false, "display the formatted time in the utc timezone and evaluate in utc")
+	_ = c.cmd.RegisterFlagCompletionFunc("zone", completeZone)
+	_ = c.cmd.RegisterFlagCompletionFunc("format", completeFormat)
+}
+
+// options are the output options of each fire time
+func (c *CronCommand) options() options {
+	return options{
+		Milliseconds: *c.milliseconds,
+		Format:       *c.format,
+		Zone:         *c.zone,
+		Local:        *c.local,
+		UTC:          *c.utc,
+	}
+}
+
+// RunE prints the fire times
+func (c *CronCommand) RunE(expr string) error {
+	if *c.count < 1 {
+		return errors.New("-n must be at least 1")
+	}
+	schedule, err := dat.ParseCron(expr)
+	if err != nil {
+		return err
+	}
+
+	// the schedule is evaluated in its CRON_TZ, or the zone that is displayed
+	loc := time.Local
+	switch {
+	case *c.zone != "":
+		if loc, err = dat.LoadZone(*c.zone); err != nil {
+			return err
+		}
+	case *c.utc:
+		loc = time.UTC
+	}
+
+	from := timeNow()
+	if *c.from != "now" {
+		precision := dat.Seconds
+		if *c.milliseconds {
+			precision = dat.Milliseconds
+		}
+		conv := dat.NewConverter(dat.WithPrecision(precision), dat.WithTimeFormats(*c.tf))
+		parsed, err := conv.Parse(*c.from)
+		if err != nil {
+			return err
+		}
+		from = parsed.Time
+	}
+
+	step := schedule.Next
+	if *c.previous {
+		step = schedule.Prev
+	}
+	opts := c.options()
+	tm := from.In(loc)
+	for i := 0; i < *c.count; i++ {
+		if tm, err = step(tm); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprint(stdOut, buildOutput(tm, opts)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
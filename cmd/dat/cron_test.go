@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Setheck/dat/pkg/dat"
+)
+
+func TestCronCommand_ParseFlags(t *testing.T) {
+	cc := NewCronCommand()
+	cc.ParseFlags()
+
+	fset := cc.cmd.Flags()
+	for _, name := range []string{"from", "count", "prev", "milliseconds", "tf", "format", "zone", "local", "utc"} {
+		assert.NotNil(t, fset.Lookup(name), name)
+	}
+	assert.Equal(t, 5, *cc.count)
+	assert.Equal(t, "now", *cc.from)
+}
+
+func TestCronCommand_RunE(t *testing.T) {
+	saveStdOut := stdOut
+	saveTimeNow := timeNow
+	defer func() {
+		stdOut = saveStdOut
+		timeNow = saveTimeNow
+	}()
+	timeNow = func() time.Time {
+		return time.Unix(1700000000, 0)
+	}
+
+	tests := []struct {
+		name  string
+		expr  string
+		flags []string
+		want  string
+		err   error
+	}{
+		{"hourly", "@hourly", []string{"-n", "2"},
+			"1700002800\n1700006400\n", nil},
+		{"previous", "@hourly", []string{"-n", "2", "--prev"},
+			"1699999200\n1699995600\n", nil},
+		{"from", "*/30 * * * * *", []string{"-n", "2", "--from", "1700000100000", "-m"},
+			"1700000130000\n1700000160000\n", nil},
+		{"zone", "0 9 * * mon-fri", []string{"-n", "3", "-z", "America/New_York", "-f", "Mon 2006-01-02 15:04 MST"},
+			"Wed 2023-11-15 09:00 EST\nThu 2023-11-16 09:00 EST\nFri 2023-11-17 09:00 EST\n", nil},
+		{"cron tz", "CRON_TZ=Asia/Tokyo 0 9 1 * *", []string{"-n", "1", "-u"},
+			"12/01/2023 00:00:00 +0000\n", nil},
+		{"time format", "@monthly", []string{"-n", "1", "--tf", "--from", "2024-01-31T12:00:00Z", "-z", "UTC", "-f", "rfc3339"},
+			"2024-02-01T00:00:00Z\n", nil},
+		{"never", "0 0 30 feb *", nil, "", dat.ErrNoFireTime},
+		{"bad from", "@daily", []string{"--from", "asdf"}, "", dat.ErrNotAnEpoch},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outputBuffer := new(bytes.Buffer)
+			stdOut = outputBuffer
+
+			cc := NewCronCommand()
+			cc.ParseFlags()
+			assert.NoError(t, cc.cmd.Flags().Parse(test.flags))
+			err := cc.RunE(test.expr)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, outputBuffer.String())
+		})
+	}
+
+	for _, args := range [][]string{{"* * *"}, {"@daily", "-n", "0"}, {"@daily", "-z", "Mars/Olympus"}} {
+		cc := NewCronCommand()
+		cc.ParseFlags()
+		assert.NoError(t, cc.cmd.Flags().Parse(args[1:]))
+		assert.Error(t, cc.RunE(args[0]))
+	}
+}
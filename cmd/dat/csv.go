@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// CSVCommand converts epoch columns of csv or tsv
+type CSVCommand struct {
+	cmd *cobra.Command
+
+	columns      *[]string
+	suffix       *string
+	tsv          *bool
+	noHeader     *bool
+	milliseconds *bool
+	tf           *bool
+	format       *string
+	delta        *string
+	zone         *string
+	local        *bool
+	utc          *bool
+}
+
+// NewCSVCommand creates a new instance of a CSVCommand
+func NewCSVCommand() *CSVCommand {
+	cc := &CSVCommand{}
+	cc.cmd = &cobra.Command{
+		Use:   "csv [file]",
+		Short: "convert epoch columns of csv or tsv",
+		Long: `csv reads csv, or tsv with --tsv or a .tsv file, from the file or stdin and converts the
+--column values, given by header name or by position from 1. Values are replaced in place,
+or written to a new column after each one named with --suffix, padding short rows. The output is formatted as it
+is for a single epoch, and rows keep their quoting, headers and line endings. Empty values are
+left as they are, and values that cannot be converted are reported on stderr.`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cc.RunE(args)
+		},
+	}
+	return cc
+}
+
+// ParseFlags parse and assign flags
+func (c *CSVCommand) ParseFlags() {
+	flgs := c.cmd.Flags()
+	c.columns = flgs.StringSliceP("column", "c", nil, "columns to convert by header name or position from 1 (ex: created_at,updated_at)")
+	c.suffix = flgs.String("suffix", "", "write converted values to a new column named with this suffix after each column (ex: _utc)")
+	c.tsv = flgs.Bool("tsv", false, "read and write tab separated values")
+	c.noHeader = flgs.Bool("no-header", false, "the first row is data, columns are given by position")
+	c.milliseconds = flgs.BoolP("milliseconds", "m", false, "epochs in milliseconds")
+	c.tf = flgs.BoolP("tf", "t", false, "attempt to parse values as a known time format")
+	c.format = flgs.StringP("format", "f", "", "https://golang.org/pkg/time/ format for time output including constant names")
+	c.delta = flgs.StringP("delta", "d", "", "a duration in which to modify each epoch (ex:+2h3s, or +5bd business days)")
+	c.zone = flgs.StringP("zone", "z", "", "convert to a specific time zone by tz database name, offset such as +05:30 or POSIX TZ string")
+	c.local = flgs.BoolP("local", "l", false, "convert to the formatted time in the local timezone")
+	c.utc = flgs.BoolP("utc", "u", false, "convert to the formatted time in the utc timezone")
+	_ = c.cmd.MarkFlagRequired("column")
+	_ = c.cmd.RegisterFlagCompletionFunc("zone", completeZone)
+	_ = c.cmd.RegisterFlagCompletionFunc("format", completeFormat)
+	_ = c.cmd.RegisterFlagCompletionFunc("delta", completeDelta)
+}
+
+// options are the output options of each value
+func (c *CSVCommand) options() options {
+	return options{
+		Milliseconds: *c.milliseconds,
+		Tf:           *c.tf,
+		Format:       *c.format,
+		Delta:        *c.delta,
+		Zone:         *c.zone,
+		Local:        *c.local,
+		UTC:          *c.utc,
+	}
+}
+
+// RunE converts the columns
+func (c *CSVCommand) RunE(args []string) error {
+	var in io.Reader = stdIn
+	delim := ','
+	if *c.tsv {
+		delim = '\t'
+	}
+	if len(args) > 0 {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+		if strings.EqualFold(filepath.Ext(args[0]), ".tsv") {
+			delim = '\t'
+		}
+	}
+
+	opts := c.options()
+	conv := opts.converter()
+	r := bufio.NewReader(in)
+	w := bufio.NewWriter(stdOut)
+	defer w.Flush()
+
+	var columns []int
+	failed := &inputsError{}
+	for row := 1; ; row++ {
+		rec, err := readCSVRecord(r, delim)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("row %d: %w", row, err)
+		}
+
+		if columns == nil {
+			if columns, err = csvColumns(*c.columns, rec, *c.noHeader); err != nil {
+				return err
+			}
+			if !*c.noHeader {
+				if *c.suffix != "" {
+					for _, i := range columns {
+						rec.insert(i, csvQuote(rec.value(i)+*c.suffix, rec.quoted(i), delim))
+					}
+				}
+				if err := rec.write(w, delim); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		// columns are converted from the last so inserting keeps the positions of the others
+		for _, i := range columns {
+			if i >= len(rec.fields) {
+				if *c.suffix == "" {
+					continue
+				}
+				// a short row is padded so the new column lines up with its header
+				for len(rec.fields) <= i {
+					rec.fields = append(rec.fields, "")
+				}
+			}
+			value := rec.value(i)
+			converted := value
+			if strings.TrimSpace(value) != "" {
+				failed.total++
+				parsed, err := conv.Parse(strings.TrimSpace(value))
+				if err != nil {
+					fmt.Fprintf(stdErr, "warning: row %d column %d: %s\n", row, i+1, err)
+					if failed.first == nil {
+						failed.first = err
+					}
+					failed.failed++
+				} else {
+					converted = strings.TrimSuffix(buildOutput(parsed.Time, opts), "\n")
+				}
+			}
+			field := csvQuote(converted, rec.quoted(i), delim)
+			if *c.suffix != "" {
+				rec.insert(i, field)
+			} else {
+				rec.fields[i] = field
+			}
+		}
+		if err := rec.write(w, delim); err != nil {
+			return err
+		}
+	}
+	if failed.failed > 0 {
+		return failed
+	}
+	return nil
+}
+
+// csvColumns resolves column names and positions to indexes, in descending order
+func csvColumns(names []string, header *csvRecord, noHeader bool) ([]int, error) {
+	if len(names) == 0 {
+		return nil, errors.New("no columns to convert, use --column")
+	}
+	seen := make(map[int]bool)
+	var columns []int
+	for _, name := range names {
+		index := -1
+		if !noHeader {
+			for i := range header.fields {
+				if header.value(i) == name {
+					index = i
+					break
+				}
+			}
+		}
+		if index < 0 {
+			n, err := strconv.Atoi(name)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("unknown column %q", name)
+			}
+			index = n - 1
+		}
+		if index >= len(header.fields) {
+			return nil, fmt.Errorf("column %s is past the %d columns of the first row", name, len(header.fields))
+		}
+		if !seen[index] {
+			seen[index] = true
+			columns = append(columns, index)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(columns)))
+	return columns, nil
+}
+
+// csvRecord is a row of raw fields, quotes included, and the line ending that ended it
+type csvRecord struct {
+	fields []string
+	eol    string
+}
+
+// quoted reports whether the field was quoted
+func (c *csvRecord) quoted(i int) bool {
+	return strings.HasPrefix(c.fields[i], `"`)
+}
+
+// value is the field without its quotes
+func (c *csvRecord) value(i int) string {
+	f := c.fields[i]
+	if len(f) >= 2 && c.quoted(i) && strings.HasSuffix(f, `"`) {
+		return strings.ReplaceAll(f[1:len(f)-1], `""`, `"`)
+	}
+	return f
+}
+
+// insert adds a raw field after the field i
+func (c *csvRecord) insert(i int, field string) {
+	c.fields = append(c.fields, "")
+	copy(c.fields[i+2:], c.fields[i+1:])
+	c.fields[i+1] = field
+}
+
+// write writes the record as it was read
+func (c *csvRecord) write(w io.Writer, delim rune) error {
+	_, err := io.WriteString(w, strings.Join(c.fields, string(delim))+c.eol)
+	return err
+}
+
+// csvQuote quotes a value when the field it replaces was quoted or when it needs to be
+func csvQuote(value string, quoted bool, delim rune) string {
+	if quoted || strings.ContainsAny(value, string(delim)+"\"\r\n") {
+		return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+	}
+	return value
+}
+
+// readCSVRecord reads the raw fields of a record, which may span lines inside quotes
+func readCSVRecord(r *bufio.Reader, delim rune) (*csvRecord, error) {
+	rec := &csvRecord{}
+	var field strings.Builder
+	inQuotes, read := false, false
+	for {
+		ch, _, err := r.ReadRune()
+		if err == io.EOF {
+			if !read {
+				return nil, io.EOF
+			}
+			if inQuotes {
+				return nil, errors.New("unterminated quoted field")
+			}
+			rec.fields = append(rec.fields, field.String())
+			return rec, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		read = true
+
+		switch {
+		case ch == '"' && field.Len() == 0 && !inQuotes:
+			inQuotes = true
+		case ch == '"' && inQuotes:
+			// a doubled quote is a quote, otherwise the quotes end
+			next, _, err := r.ReadRune()
+			if err == nil && next == '"' {
+				field.WriteString(`""`)
+				continue
+			}
+			if err == nil {
+				_ = r.UnreadRune()
+			}
+			inQuotes = false
+		case inQuotes:
+		case ch == delim:
+			rec.fields = append(rec.fields, field.String())
+			field.Reset()
+			continue
+		case ch == '\n':
+			f := field.String()
+			rec.eol = "\n"
+			if strings.HasSuffix(f, "\r") {
+				f, rec.eol = f[:len(f)-1], "\r\n"
+			}
+			rec.fields = append(rec.fields, f)
+			return rec, nil
+		}
+		field.WriteRune(ch)
+	}
+}
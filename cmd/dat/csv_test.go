@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Setheck/dat/pkg/dat"
+)
+
+func TestCSVCommand_ParseFlags(t *testing.T) {
+	cc := NewCSVCommand()
+	cc.ParseFlags()
+
+	fset := cc.cmd.Flags()
+	for _, name := range []string{"column", "suffix", "tsv", "no-header", "milliseconds", "tf", "format", "delta", "zone", "local", "utc"} {
+		assert.NotNil(t, fset.Lookup(name), name)
+	}
+	assert.NotNil(t, fset.ShorthandLookup("c"))
+}
+
+func TestCSVCommand_RunE(t *testing.T) {
+	saveStdIn := stdIn
+	saveStdOut := stdOut
+	saveStdErr := stdErr
+	defer func() {
+		stdIn = saveStdIn
+		stdOut = saveStdOut
+		stdErr = saveStdErr
+	}()
+
+	const table = "id,\"created_at\",note,updated_at\r\n" +
+		"1,\"1700000000\",\"said \"\"hi\"\", then\nleft\",1700003600\r\n" +
+		"2,,plain,1700007200\r\n"
+
+	tests := []struct {
+		name  string
+		input string
+		flags []string
+		want  string
+		err   error
+	}{
+		{"in place", table, []string{"-c", "created_at,4", "-z", "UTC", "-f", "rfc3339"},
+			"id,\"created_at\",note,updated_at\r\n" +
+				"1,\"2023-11-14T22:13:20Z\",\"said \"\"hi\"\", then\nleft\",2023-11-14T23:13:20Z\r\n" +
+				"2,,plain,2023-11-15T00:13:20Z\r\n", nil},
+		{"new columns", table, []string{"-c", "created_at", "--suffix", "_utc", "-u"},
+			"id,\"created_at\",\"created_at_utc\",note,updated_at\r\n" +
+				"1,\"1700000000\",\"11/14/2023 22:13:20 +0000\",\"said \"\"hi\"\", then\nleft\",1700003600\r\n" +
+				"2,,,plain,1700007200\r\n", nil},
+		{"ragged rows", "id,ts,note,end\n1\n2,1700000000\n3,1700000000,x,1700003600\n",
+			[]string{"-c", "ts,end", "--suffix", "_utc", "-z", "UTC", "-f", "15:04"},
+			"id,ts,ts_utc,note,end,end_utc\n" +
+				"1,,,,,\n" +
+				"2,1700000000,22:13,,,\n" +
+				"3,1700000000,22:13,x,1700003600,23:13\n", nil},
+		{"ragged rows in place", "id,ts,note,end\n1\n2,1700000000\n", []string{"-c", "ts,end", "-z", "UTC", "-f", "15:04"},
+			"id,ts,note,end\n1\n2,22:13\n", nil},
+		{"fixed offset zone", "ts\n1700000000\n", []string{"-c", "ts", "-z", "+05:30"},
+			"ts\n11/15/2023 03:43:20 +0530\n", nil},
+		{"milliseconds and delta", "ts\n1700000000000\n", []string{"-c", "ts", "-m", "-d", "1h"},
+			"ts\n1700003600000\n", nil},
+		{"quoted output", "ts\n1700000000\n", []string{"-c", "1", "-z", "UTC", "-f", "Jan 2, 2006"},
+			"ts\n\"Nov 14, 2023\"\n", nil},
+		{"tsv", "a\tb\n1\t1700000000", []string{"--tsv", "-c", "b", "-z", "UTC", "-f", "2006-01-02"},
+			"a\tb\n1\t2023-11-14", nil},
+		{"no header", "1700000000,x\n", []string{"--no-header", "-c", "1", "-z", "UTC", "-f", "2006"},
+			"2023,x\n", nil},
+		{"bad value", "ts\nsoon\n1700000000\n", []string{"-c", "ts", "-z", "UTC", "-f", "2006"},
+			"ts\nsoon\n2023\n", dat.ErrNotAnEpoch},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdIn = strings.NewReader(test.input)
+			outputBuffer := new(bytes.Buffer)
+			stdOut = outputBuffer
+			stdErr = new(bytes.Buffer)
+
+			cc := NewCSVCommand()
+			cc.ParseFlags()
+			assert.NoError(t, cc.cmd.Flags().Parse(test.flags))
+			err := cc.RunE(nil)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.want, outputBuffer.String())
+		})
+	}
+
+	t.Run("tsv file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.tsv")
+		require.NoError(t, os.WriteFile(path, []byte("ts\tname\n1700000000\tlaunch\n"), 0o600))
+		outputBuffer := new(bytes.Buffer)
+		stdOut = outputBuffer
+
+		cc := NewCSVCommand()
+		cc.ParseFlags()
+		assert.NoError(t, cc.cmd.Flags().Parse([]string{"-c", "ts", "-d", "-1h"}))
+		assert.NoError(t, cc.RunE([]string{path}))
+		assert.Equal(t, "ts\tname\n1699996400\tlaunch\n", outputBuffer.String())
+	})
+
+	for _, test := range []struct {
+		input string
+		flags []string
+	}{
+		{"a,b\n1,2\n", []string{"-c", "c"}},
+		{"a,b\n1,2\n", []string{"-c", "0"}},
+		// past the width of the header, with or without a new column
+		{"a,b\n1,1700000000\n", []string{"-c", "5"}},
+		{"a,b\n1,1700000000\n", []string{"-c", "5", "--suffix", "_x"}},
+		{"1,1700000000\n", []string{"--no-header", "-c", "3"}},
+		{"a,b\n\"1,2\n", []string{"-c", "a"}},
+		{"a\n", nil},
+	} {
+		stdIn = strings.NewReader(test.input)
+		stdOut = new(bytes.Buffer)
+		cc := NewCSVCommand()
+		cc.ParseFlags()
+		assert.NoError(t, cc.cmd.Flags().Parse(test.flags))
+		assert.Error(t, cc.RunE(nil), test.input)
+	}
+	cc := NewCSVCommand()
+	cc.ParseFlags()
+	assert.Error(t, cc.RunE([]string{"missing.csv"}))
+}
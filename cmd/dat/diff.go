@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Setheck/dat/pkg/dat"
+)
+
+// DiffCommand prints the time between two instants
+type DiffCommand struct {
+	cmd *cobra.Command
+
+	business     *bool
+	weekend      *string
+	holidays     *[]string
+	milliseconds *bool
+	tf           *bool
+	zone         *string
+	utc          *bool
+}
+
+// NewDiffCommand creates a new instance of a DiffCommand
+func NewDiffCommand() *DiffCommand {
+	dc := &DiffCommand{}
+	dc.cmd = &cobra.Command{
+		Use:   "diff <start> <end>",
+		Short: "print the time from start to end",
+		Long: `diff prints the duration from start to end, negative when end is before start.
+Start and end are epochs, times with --tf, or now. With --business it prints the number of
+business days after the day of start up to the day of end, skipping the --weekend and the
+holidays of the --holidays calendars, counted on the calendar of the --zone (local by default).`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return dc.RunE(args[0], args[1])
+		},
+	}
+	return dc
+}
+
+// ParseFlags parse and assign flags
+func (d *DiffCommand) ParseFlags() {
+	flgs := d.cmd.Flags()
+	d.business = flgs.BoolP("business", "b", false, "count business days instead of printing a duration")
+	d.weekend = flgs.String("weekend", "", "weekend days of business days (ex: sat,sun or fri-sat, default sat,sun)")
+	d.holidays = flgs.StringSlice("holidays", nil, "iCalendar (.ics) or YAML (.yaml) files of holidays that are not business days")
+	d.milliseconds = flgs.BoolP("milliseconds", "m", false, "epochs in milliseconds")
+	d.tf = flgs.BoolP("tf", "t", false, "attempt to parse start and end as a known time format")
+	d.zone = flgs.StringP("zone", "z", "", "count business days in a specific time zone by tz database name, offset such as +05:30 or POSIX TZ string")
+	d.utc = flgs.BoolP("utc", "u", false, "count business days in the utc timezone")
+	_ = d.cmd.RegisterFlagCompletionFunc("zone", completeZone)
+}
+
+// RunE prints the difference
+func (d *DiffCommand) RunE(startArg, endArg string) error {
+	precision := dat.Seconds
+	if *d.milliseconds {
+		precision = dat.Milliseconds
+	}
+	conv := dat.NewConverter(dat.WithPrecision(precision), dat.WithTimeFormats(*d.tf))
+	var bounds [2]time.Time
+	for i, arg := range []string{startArg, endArg} {
+		if arg == "now" {
+			bounds[i] = timeNow()
+			continue
+		}
+		parsed, err := conv.Parse(arg)
+		if err != nil {
+			return err
+		}
+		bounds[i] = parsed.Time
+	}
+	start, end := bounds[0], bounds[1]
+
+	if !*d.business {
+		_, err := fmt.Fprintln(stdOut, end.Sub(start))
+		return err
+	}
+
+	// business days are those of the calendar in the zone
+	loc := time.Local
+	var err error
+	switch {
+	case *d.zone != "":
+		if loc, err = dat.LoadZone(*d.zone); err != nil {
+			return err
+		}
+	case *d.utc:
+		loc = time.UTC
+	}
+	cal, err := businessCalendar(*d.weekend, *d.holidays)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdOut, cal.BusinessDays(start.In(loc), end.In(loc)))
+	return err
+}
+
+// businessCalendar builds a calendar from holiday files, the weekend replaces the default
+// weekend and any weekend set by the files when it is not empty.
+func businessCalendar(weekend string, holidays []string) (*dat.BusinessCalendar, error) {
+	cal := dat.NewBusinessCalendar()
+	for _, path := range holidays {
+		if err := cal.LoadHolidays(path); err != nil {
+			return nil, err
+		}
+	}
+	if weekend != "" {
+		days, err := dat.ParseWeekend(weekend)
+		if err != nil {
+			return nil, err
+		}
+		cal.SetWeekend(days)
+	}
+	return cal, nil
+}
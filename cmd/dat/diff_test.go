@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Setheck/dat/pkg/dat"
+)
+
+func TestDiffCommand_ParseFlags(t *testing.T) {
+	dc := NewDiffCommand()
+	dc.ParseFlags()
+
+	fset := dc.cmd.Flags()
+	for _, name := range []string{"business", "weekend", "holidays", "milliseconds", "tf", "zone", "utc"} {
+		assert.NotNil(t, fset.Lookup(name), name)
+	}
+	assert.NotNil(t, fset.ShorthandLookup("b"))
+}
+
+func TestDiffCommand_RunE(t *testing.T) {
+	saveStdOut := stdOut
+	saveTimeNow := timeNow
+	defer func() {
+		stdOut = saveStdOut
+		timeNow = saveTimeNow
+	}()
+	// a wednesday
+	timeNow = func() time.Time {
+		return time.Date(2024, 12, 18, 12, 0, 0, 0, time.UTC)
+	}
+
+	holidays := filepath.Join(t.TempDir(), "holidays.yaml")
+	require.NoError(t, os.WriteFile(holidays, []byte("holidays:\n  - date: 12-25\n    name: Christmas Day\n"), 0o600))
+
+	tests := []struct {
+		name       string
+		start, end string
+		flags      []string
+		want       string
+		err        error
+	}{
+		{"duration", "1700000000", "1700005400", nil, "1h30m0s\n", nil},
+		{"negative", "1700005400", "1700000000", nil, "-1h30m0s\n", nil},
+		{"milliseconds", "1700000000000", "1700000000250", []string{"-m"}, "250ms\n", nil},
+		{"business days", "now", "2024-12-27T12:00:00Z", []string{"--tf", "-b", "-u"}, "7\n", nil},
+		{"business days with holidays", "now", "2024-12-27T12:00:00Z", []string{"--tf", "-b", "-u", "--holidays", holidays}, "6\n", nil},
+		{"business days with a weekend", "now", "2024-12-27T12:00:00Z", []string{"--tf", "-b", "-u", "--weekend", "fri-sat"}, "6\n", nil},
+		{"business days back", "2024-12-27T12:00:00Z", "now", []string{"--tf", "-b", "-z", "UTC"}, "-7\n", nil},
+		{"bad end", "1700000000", "asdf", nil, "", dat.ErrNotAnEpoch},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outputBuffer := new(bytes.Buffer)
+			stdOut = outputBuffer
+
+			dc := NewDiffCommand()
+			dc.ParseFlags()
+			assert.NoError(t, dc.cmd.Flags().Parse(test.flags))
+			err := dc.RunE(test.start, test.end)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, outputBuffer.String())
+		})
+	}
+
+	for _, flags := range [][]string{{"-b", "--weekend", "someday"}, {"-b", "--holidays", "missing.ics"}, {"-b", "-z", "Mars/Olympus"}} {
+		dc := NewDiffCommand()
+		dc.ParseFlags()
+		assert.NoError(t, dc.cmd.Flags().Parse(flags))
+		assert.Error(t, dc.RunE("1700000000", "1700007200"))
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Setheck/dat/pkg/dat"
+)
+
+// exit codes, scripts can branch on the kind of failure
+const (
+	ExitOK            = 0
+	ExitError         = 1
+	ExitNotAnEpoch    = 3
+	ExitOutOfRange    = 4
+	ExitUnknownFormat = 5
+	ExitAmbiguous     = 6
+	ExitNotAnID       = 7
+	ExitInvalidLayout = 8
+)
+
+// ExitCode maps an error returned by the command to a process exit code.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, dat.ErrNotAnEpoch):
+		return ExitNotAnEpoch
+	case errors.Is(err, dat.ErrOutOfRange):
+		return ExitOutOfRange
+	case errors.Is(err, dat.ErrUnknownFormat):
+		return ExitUnknownFormat
+	case errors.Is(err, dat.ErrAmbiguous):
+		return ExitAmbiguous
+	case errors.Is(err, dat.ErrNotAnID):
+		return ExitNotAnID
+	case errors.Is(err, dat.ErrInvalidLayout):
+		return ExitInvalidLayout
+	default:
+		return ExitError
+	}
+}
+
+// PrintHints writes any suggested fixes carried by err.
+func PrintHints(w io.Writer, err error) {
+	var pe *dat.ParseError
+	if !errors.As(err, &pe) {
+		return
+	}
+	for _, s := range pe.Suggestions {
+		fmt.Fprintln(w, "hint:", s)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Setheck/dat/pkg/dat"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"generic", assert.AnError, ExitError},
+		{"not an epoch", &dat.ParseError{Err: dat.ErrNotAnEpoch}, ExitNotAnEpoch},
+		{"out of range", &dat.ParseError{Err: dat.ErrOutOfRange}, ExitOutOfRange},
+		{"unknown format", &dat.ParseError{Err: dat.ErrUnknownFormat}, ExitUnknownFormat},
+		{"ambiguous", &dat.ParseError{Err: dat.ErrAmbiguous}, ExitAmbiguous},
+		{"not an id", &dat.ParseError{Err: dat.ErrNotAnID}, ExitNotAnID},
+		{"invalid layout", &dat.ParseError{Err: dat.ErrInvalidLayout}, ExitInvalidLayout},
+		{"wrapped", fmt.Errorf("wrapped: %w", &dat.ParseError{Err: dat.ErrNotAnEpoch}), ExitNotAnEpoch},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, ExitCode(test.err))
+		})
+	}
+}
+
+func TestPrintHints(t *testing.T) {
+	buf := new(bytes.Buffer)
+	PrintHints(buf, &dat.ParseError{Err: dat.ErrNotAnEpoch, Suggestions: []string{"one", "two"}})
+	assert.Equal(t, "hint: one\nhint: two\n", buf.String())
+
+	buf.Reset()
+	PrintHints(buf, assert.AnError)
+	assert.Empty(t, buf.String())
+}
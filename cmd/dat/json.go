@@ -0,0 +1,528 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Setheck/dat/pkg/dat"
+)
+
+// JSONCommand converts epoch fields of json or ndjson
+type JSONCommand struct {
+	cmd *cobra.Command
+
+	paths        *[]string
+	suffix       *string
+	indent       *int
+	milliseconds *bool
+	seconds      *bool
+	tf           *bool
+	format       *string
+	delta        *string
+	zone         *string
+	local        *bool
+	utc          *bool
+}
+
+// NewJSONCommand creates a new instance of a JSONCommand
+func NewJSONCommand() *JSONCommand {
+	jc := &JSONCommand{}
+	jc.cmd = &cobra.Command{
+		Use:   "json [file]",
+		Short: "convert epoch fields of json or ndjson",
+		Long: `json reads json values, one document or one per line, from the file or stdin and converts the
+fields at each --path. A path is a jq style path such as .events[].ts, .meta.created, .items[0].at,
+.["odd key"] or . for the whole value. Values are replaced, or written to a sibling field named with
+--suffix. Epochs are read in seconds or milliseconds, whichever is a plausible time, unless -m or -s
+is given, numbers may have a fraction or an exponent, and are formatted as they are for a single epoch. Missing fields and nulls are left as they are,
+and values that cannot be converted are reported on stderr.`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jc.RunE(args)
+		},
+	}
+	return jc
+}
+
+// ParseFlags parse and assign flags
+func (c *JSONCommand) ParseFlags() {
+	flgs := c.cmd.Flags()
+	c.paths = flgs.StringArray("path", nil, "path of the fields to convert, may be repeated (ex: .events[].ts)")
+	c.suffix = flgs.String("suffix", "", "write converted values to a sibling field named with this suffix (ex: _human)")
+	c.indent = flgs.Int("indent", 0, "indent output by this many spaces, output is one value per line by default")
+	c.milliseconds = flgs.BoolP("milliseconds", "m", false, "epochs in milliseconds")
+	c.seconds = flgs.BoolP("seconds", "s", false, "epochs in seconds")
+	c.tf = flgs.BoolP("tf", "t", false, "attempt to parse values as a known time format")
+	c.format = flgs.StringP("format", "f", "", "https://golang.org/pkg/time/ format for time output including constant names")
+	c.delta = flgs.StringP("delta", "d", "", "a duration in which to modify each epoch (ex:+2h3s, or +5bd business days)")
+	c.zone = flgs.StringP("zone", "z", "", "convert to a specific time zone by tz database name, offset such as +05:30 or POSIX TZ string")
+	c.local = flgs.BoolP("local", "l", false, "convert to the formatted time in the local timezone")
+	c.utc = flgs.BoolP("utc", "u", false, "convert to the formatted time in the utc timezone")
+	_ = c.cmd.MarkFlagRequired("path")
+	_ = c.cmd.RegisterFlagCompletionFunc("zone", completeZone)
+	_ = c.cmd.RegisterFlagCompletionFunc("format", completeFormat)
+	_ = c.cmd.RegisterFlagCompletionFunc("delta", completeDelta)
+}
+
+// options are the output options of each value
+func (c *JSONCommand) options() options {
+	return options{
+		Milliseconds: *c.milliseconds,
+		Tf:           *c.tf,
+		Format:       *c.format,
+		Delta:        *c.delta,
+		Zone:         *c.zone,
+		Local:        *c.local,
+		UTC:          *c.utc,
+	}
+}
+
+// RunE converts the fields
+func (c *JSONCommand) RunE(args []string) error {
+	if *c.milliseconds && *c.seconds {
+		return errors.New("only one of --milliseconds and --seconds may be given")
+	}
+	if len(*c.paths) == 0 {
+		return errors.New("no fields to convert, use --path")
+	}
+	var paths [][]jsonSegment
+	for _, p := range *c.paths {
+		segments, err := parseJSONPath(p)
+		if err != nil {
+			return err
+		}
+		if *c.suffix != "" && (len(segments) == 0 || segments[len(segments)-1].kind != segmentKey) {
+			return fmt.Errorf("path %q does not end in a field, --suffix needs a field to add a sibling to", p)
+		}
+		paths = append(paths, segments)
+	}
+
+	var in io.Reader = stdIn
+	if len(args) > 0 {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	indent := ""
+	if *c.indent > 0 {
+		indent = strings.Repeat(" ", *c.indent)
+	}
+
+	dec := json.NewDecoder(in)
+	dec.UseNumber()
+	w := bufio.NewWriter(stdOut)
+	defer w.Flush()
+
+	failed := &inputsError{}
+	for n := 1; ; n++ {
+		doc, err := decodeJSON(dec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("value %d: %w", n, err)
+		}
+
+		for i, segments := range paths {
+			convert := func(v interface{}) (interface{}, bool) {
+				if v == nil || v == "" {
+					return nil, false
+				}
+				failed.total++
+				converted, err := c.convert(v)
+				if err != nil {
+					fmt.Fprintf(stdErr, "warning: value %d %s: %s\n", n, (*c.paths)[i], err)
+					if failed.first == nil {
+						failed.first = err
+					}
+					failed.failed++
+					return nil, false
+				}
+				return converted, true
+			}
+
+			if len(segments) == 0 {
+				if converted, ok := convert(doc); ok {
+					doc = converted
+				}
+				continue
+			}
+			walkJSON(doc, segments, func(container interface{}, key string, index int) {
+				switch t := container.(type) {
+				case *jsonObject:
+					converted, ok := convert(t.values[key])
+					if !ok {
+						return
+					}
+					if *c.suffix != "" {
+						t.insertAfter(key, key+*c.suffix, converted)
+					} else {
+						t.values[key] = converted
+					}
+				case []interface{}:
+					if converted, ok := convert(t[index]); ok {
+						t[index] = converted
+					}
+				}
+			})
+		}
+
+		var b strings.Builder
+		encodeJSON(&b, doc, indent, 0)
+		b.WriteByte('\n')
+		if _, err := w.WriteString(b.String()); err != nil {
+			return err
+		}
+	}
+	if failed.failed > 0 {
+		return failed
+	}
+	return nil
+}
+
+// convert converts a number or a string, a number stays a number when the output is an epoch
+func (c *JSONCommand) convert(v interface{}) (interface{}, error) {
+	var input string
+	fraction := new(big.Rat)
+	switch t := v.(type) {
+	case json.Number:
+		input = t.String()
+		if !*c.tf {
+			if whole, frac, ok := splitJSONNumber(t); ok {
+				input, fraction = whole, frac
+			}
+		}
+	case string:
+		input = strings.TrimSpace(t)
+	case bool:
+		return nil, fmt.Errorf("a boolean %w", dat.ErrNotAnEpoch)
+	case *jsonObject:
+		return nil, fmt.Errorf("an object %w", dat.ErrNotAnEpoch)
+	default:
+		return nil, fmt.Errorf("an array %w", dat.ErrNotAnEpoch)
+	}
+
+	opts := c.options()
+	if !*c.milliseconds && !*c.seconds {
+		if epoch, err := strconv.ParseInt(input, 10, 64); err == nil {
+			opts.Milliseconds = dat.GuessPrecision(epoch) == dat.Milliseconds
+		}
+	}
+	parsed, err := opts.converter().Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	unit := time.Second
+	if opts.Milliseconds {
+		unit = time.Millisecond
+	}
+	if fraction.Sign() != 0 {
+		nanos := new(big.Rat).Mul(fraction, new(big.Rat).SetInt64(int64(unit)))
+		parsed.Time = parsed.Time.Add(time.Duration(new(big.Int).Quo(nanos.Num(), nanos.Denom()).Int64()))
+	}
+	out := strings.TrimSuffix(buildOutput(parsed.Time, opts), "\n")
+	formatted := opts.Format != "" || opts.Zone != "" || opts.Local || opts.UTC
+	if _, isNumber := v.(json.Number); isNumber && !formatted {
+		if epoch, ok := new(big.Rat).SetString(out); ok && epoch.IsInt() {
+			if fraction.Sign() != 0 {
+				// the epoch is the whole unit the time is in, the fraction of the input carries on past it
+				epoch.Add(epoch, big.NewRat(int64(parsed.Time.Nanosecond())%int64(unit), int64(unit)))
+				out = strings.TrimRight(strings.TrimRight(epoch.FloatString(9), "0"), ".")
+			}
+			return json.Number(out), nil
+		}
+	}
+	return out, nil
+}
+
+// splitJSONNumber splits a number, which may have a fraction or an exponent, into the whole epoch
+// the converter parses and the fraction of a unit that is left
+func splitJSONNumber(n json.Number) (string, *big.Rat, bool) {
+	value, ok := new(big.Rat).SetString(n.String())
+	if !ok {
+		return "", nil, false
+	}
+	// Div rounds toward negative infinity for a positive denominator, keeping the fraction positive
+	whole := new(big.Int).Div(value.Num(), value.Denom())
+	return whole.String(), value.Sub(value, new(big.Rat).SetInt(whole)), true
+}
+
+// jsonObject is a json object that keeps the order of its keys
+type jsonObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// insertAfter sets the value of key, adding it after the key after when it is new
+func (o *jsonObject) insertAfter(after, key string, value interface{}) {
+	if _, ok := o.values[key]; !ok {
+		for i, k := range o.keys {
+			if k == after {
+				o.keys = append(o.keys, "")
+				copy(o.keys[i+2:], o.keys[i+1:])
+				o.keys[i+1] = key
+				break
+			}
+		}
+	}
+	o.values[key] = value
+}
+
+// decodeJSON decodes the next value, objects as *jsonObject, arrays as []interface{} and numbers as json.Number
+func decodeJSON(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	value, err := decodeJSONToken(dec, tok)
+	if err == io.EOF {
+		// the stream ended inside the value
+		err = io.ErrUnexpectedEOF
+	}
+	return value, err
+}
+
+// decodeJSONToken decodes the value that starts with tok
+func decodeJSONToken(dec *json.Decoder, tok json.Token) (interface{}, error) {
+	switch tok {
+	case json.Delim('{'):
+		obj := &jsonObject{values: make(map[string]interface{})}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyTok.(string)
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := obj.values[key]; !ok {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.values[key] = value
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := []interface{}{}
+		for dec.More() {
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err := dec.Token()
+		return arr, err
+	}
+	return tok, nil
+}
+
+// encodeJSON writes a decoded value compact, or indented when indent is not empty
+func encodeJSON(b *strings.Builder, v interface{}, indent string, depth int) {
+	newline := func(depth int) {
+		if indent != "" {
+			b.WriteByte('\n')
+			b.WriteString(strings.Repeat(indent, depth))
+		}
+	}
+	switch t := v.(type) {
+	case *jsonObject:
+		b.WriteByte('{')
+		for i, key := range t.keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			newline(depth + 1)
+			b.WriteString(jsonString(key))
+			b.WriteByte(':')
+			if indent != "" {
+				b.WriteByte(' ')
+			}
+			encodeJSON(b, t.values[key], indent, depth+1)
+		}
+		if len(t.keys) > 0 {
+			newline(depth)
+		}
+		b.WriteByte('}')
+	case []interface{}:
+		b.WriteByte('[')
+		for i, value := range t {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			newline(depth + 1)
+			encodeJSON(b, value, indent, depth+1)
+		}
+		if len(t) > 0 {
+			newline(depth)
+		}
+		b.WriteByte(']')
+	case string:
+		b.WriteString(jsonString(t))
+	case json.Number:
+		b.WriteString(t.String())
+	case bool:
+		b.WriteString(strconv.FormatBool(t))
+	default:
+		b.WriteString("null")
+	}
+}
+
+// jsonString is s as a json string, without escaping html characters
+func jsonString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// segmentKind is the kind of a step in a json path
+type segmentKind int
+
+const (
+	// segmentKey is a field of an object, .key or ["key"]
+	segmentKey segmentKind = iota
+	// segmentIndex is an element of an array, [0] or [-1] from the end
+	segmentIndex
+	// segmentEach is every element of an array or field of an object, []
+	segmentEach
+)
+
+// jsonSegment is a step in a json path
+type jsonSegment struct {
+	kind  segmentKind
+	key   string
+	index int
+}
+
+// parseJSONPath reads a jq style path such as .events[].ts, . is the whole value
+func parseJSONPath(path string) ([]jsonSegment, error) {
+	str := strings.TrimSpace(path)
+	invalid := func(reason string) error {
+		return fmt.Errorf("invalid path %q: %s", path, reason)
+	}
+	if !strings.HasPrefix(str, ".") && !strings.HasPrefix(str, "[") {
+		return nil, invalid("a path starts with .")
+	}
+	if str == "." {
+		return nil, nil
+	}
+
+	var segments []jsonSegment
+	for i := 0; i < len(str); {
+		switch str[i] {
+		case '.':
+			i++
+			if i < len(str) && str[i] == '[' {
+				continue
+			}
+			end := i
+			for end < len(str) && str[end] != '.' && str[end] != '[' {
+				end++
+			}
+			if end == i {
+				return nil, invalid("empty field name")
+			}
+			segments = append(segments, jsonSegment{kind: segmentKey, key: str[i:end]})
+			i = end
+		case '[':
+			if strings.HasPrefix(str[i:], `["`) {
+				// the name ends at the first quote that is not escaped
+				end := i + 2
+				for end < len(str) && str[end] != '"' {
+					if str[end] == '\\' {
+						end++
+					}
+					end++
+				}
+				if end+1 >= len(str) || str[end+1] != ']' {
+					return nil, invalid("unterminated field name")
+				}
+				key, err := strconv.Unquote(str[i+1 : end+1])
+				if err != nil {
+					return nil, invalid("invalid quoted field name")
+				}
+				segments = append(segments, jsonSegment{kind: segmentKey, key: key})
+				i = end + 2
+				continue
+			}
+			end := strings.IndexByte(str[i:], ']')
+			if end < 0 {
+				return nil, invalid("missing ]")
+			}
+			inner := strings.TrimSpace(str[i+1 : i+end])
+			if inner == "" {
+				segments = append(segments, jsonSegment{kind: segmentEach})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, invalid(fmt.Sprintf("invalid index %q", inner))
+				}
+				segments = append(segments, jsonSegment{kind: segmentIndex, index: index})
+			}
+			i += end + 1
+		default:
+			return nil, invalid(fmt.Sprintf("unexpected %q", str[i]))
+		}
+	}
+	return segments, nil
+}
+
+// walkJSON calls fn with the container and the key or index of every value at the path,
+// values that are missing or of another type than the path expects are skipped
+func walkJSON(v interface{}, segments []jsonSegment, fn func(container interface{}, key string, index int)) {
+	seg, rest := segments[0], segments[1:]
+	visit := func(container, child interface{}, key string, index int) {
+		if len(rest) == 0 {
+			fn(container, key, index)
+		} else {
+			walkJSON(child, rest, fn)
+		}
+	}
+	switch t := v.(type) {
+	case *jsonObject:
+		switch seg.kind {
+		case segmentKey:
+			if child, ok := t.values[seg.key]; ok {
+				visit(t, child, seg.key, 0)
+			}
+		case segmentEach:
+			for _, key := range append([]string(nil), t.keys...) {
+				visit(t, t.values[key], key, 0)
+			}
+		}
+	case []interface{}:
+		switch seg.kind {
+		case segmentIndex:
+			index := seg.index
+			if index < 0 {
+				index += len(t)
+			}
+			if index >= 0 && index < len(t) {
+				visit(t, t[index], "", index)
+			}
+		case segmentEach:
+			for i := range t {
+				visit(t, t[i], "", i)
+			}
+		}
+	}
+}
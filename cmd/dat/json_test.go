@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Setheck/dat/pkg/dat"
+)
+
+func TestJSONCommand_ParseFlags(t *testing.T) {
+	jc := NewJSONCommand()
+	jc.ParseFlags()
+
+	fset := jc.cmd.Flags()
+	for _, name := range []string{"path", "suffix", "indent", "milliseconds", "seconds", "tf", "format", "delta", "zone", "local", "utc"} {
+		assert.NotNil(t, fset.Lookup(name), name)
+	}
+}
+
+func TestJSONCommand_RunE(t *testing.T) {
+	saveStdIn := stdIn
+	saveStdOut := stdOut
+	saveStdErr := stdErr
+	defer func() {
+		stdIn = saveStdIn
+		stdOut = saveStdOut
+		stdErr = saveStdErr
+	}()
+
+	const events = `{"events":[{"ts":1700000000,"name":"a<b"},{"ts":1700000000000},{"ts":null},{}],"meta":{"created":"1700000000"}}`
+
+	tests := []struct {
+		name  string
+		input string
+		flags []string
+		want  string
+		err   error
+	}{
+		{"in place", events, []string{"--path", ".events[].ts", "--path", ".meta.created", "-z", "UTC", "-f", "rfc3339"},
+			`{"events":[{"ts":"2023-11-14T22:13:20Z","name":"a<b"},{"ts":"2023-11-14T22:13:20Z"},{"ts":null},{}],"meta":{"created":"2023-11-14T22:13:20Z"}}` + "\n", nil},
+		{"sibling fields", events, []string{"--path", ".events[].ts", "--suffix", "_human", "-z", "UTC", "-f", "2006"},
+			`{"events":[{"ts":1700000000,"ts_human":"2023","name":"a<b"},{"ts":1700000000000,"ts_human":"2023"},{"ts":null},{}],"meta":{"created":"1700000000"}}` + "\n", nil},
+		{"ndjson numbers stay numbers", "{\"at\":1700000000}\n{\"at\":1700000000000}\n", []string{"--path", ".at", "-d", "1h"},
+			"{\"at\":1700003600}\n{\"at\":1700003600000}\n", nil},
+		{"forced precision", `{"at":1700000000}`, []string{"--path", ".at", "-m", "-z", "UTC", "-f", "2006"},
+			"{\"at\":\"1970\"}\n", nil},
+		{"index and quoted key", `{"odd key":[1,1700000000]}`, []string{"--path", `.["odd key"][-1]`, "-z", "UTC", "-f", "2006"},
+			"{\"odd key\":[1,\"2023\"]}\n", nil},
+		{"escaped quoted keys", `{"a\"]b":1700000000,"x]":1700000000}`, []string{"--path", `.["a\"]b"]`, "--path", `.["x]"]`, "-z", "UTC", "-f", "2006"},
+			`{"a\"]b":"2023","x]":"2023"}` + "\n", nil},
+		{"fractional numbers", `[1700000000.123,1700000000123.5,1.7e9,-0.5]`, []string{"--path", ".[]", "-z", "UTC", "-f", "rfc3339nano"},
+			`["2023-11-14T22:13:20.123Z","2023-11-14T22:13:20.1235Z","2023-11-14T22:13:20Z","1969-12-31T23:59:59.5Z"]` + "\n", nil},
+		{"fractional numbers stay numbers", `{"at":1700000000.75}`, []string{"--path", ".at", "-d", "1h"},
+			"{\"at\":1700003600.75}\n", nil},
+		{"fractional epochs keep the fraction", `[1700000000.5,-1.5,1700000000123.25,1.7e9]`, []string{"--path", ".[]", "-s"},
+			"[1700000000.5,-1.5,1700000000123.25,1700000000]\n", nil},
+		{"whole value", "1700000000 [1700000000]", []string{"--path", ".", "-z", "UTC", "-f", "2006"},
+			"\"2023\"\n[1700000000]\n", dat.ErrNotAnEpoch},
+		{"indented", `{"a":{"ts":1700000000},"b":[]}`, []string{"--path", ".a.ts", "--indent", "2", "-z", "UTC", "-f", "2006"},
+			"{\n  \"a\": {\n    \"ts\": \"2023\"\n  },\n  \"b\": []\n}\n", nil},
+		{"bad value", `{"ts":[{"at":"soon"},{"at":true},{"at":1700000000}]}`, []string{"--path", ".ts[].at", "-z", "UTC", "-f", "2006"},
+			`{"ts":[{"at":"soon"},{"at":true},{"at":"2023"}]}` + "\n", dat.ErrNotAnEpoch},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdIn = strings.NewReader(test.input)
+			outputBuffer := new(bytes.Buffer)
+			stdOut = outputBuffer
+			stdErr = new(bytes.Buffer)
+
+			jc := NewJSONCommand()
+			jc.ParseFlags()
+			assert.NoError(t, jc.cmd.Flags().Parse(test.flags))
+			err := jc.RunE(nil)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.want, outputBuffer.String())
+		})
+	}
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "events.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"ts":1700000000}`), 0o600))
+		outputBuffer := new(bytes.Buffer)
+		stdOut = outputBuffer
+
+		jc := NewJSONCommand()
+		jc.ParseFlags()
+		assert.NoError(t, jc.cmd.Flags().Parse([]string{"--path", ".ts", "-d", "-1h"}))
+		assert.NoError(t, jc.RunE([]string{path}))
+		assert.Equal(t, "{\"ts\":1699996400}\n", outputBuffer.String())
+	})
+
+	for _, test := range []struct {
+		input string
+		flags []string
+	}{
+		{`{}`, []string{"--path", "ts"}},
+		{`{}`, []string{"--path", ".a[x]"}},
+		{`{}`, []string{"--path", `.["a\"]`}},
+		{`{}`, []string{"--path", ".a[]", "--suffix", "_human"}},
+		{`{}`, []string{"--path", ".a", "-m", "-s"}},
+		{`{"a":`, []string{"--path", ".a"}},
+		{`{}`, nil},
+	} {
+		stdIn = strings.NewReader(test.input)
+		stdOut = new(bytes.Buffer)
+		jc := NewJSONCommand()
+		jc.ParseFlags()
+		assert.NoError(t, jc.cmd.Flags().Parse(test.flags))
+		assert.Error(t, jc.RunE(nil), test.input)
+	}
+	jc := NewJSONCommand()
+	jc.ParseFlags()
+	assert.NoError(t, jc.cmd.Flags().Parse([]string{"--path", ".ts"}))
+	assert.Error(t, jc.RunE([]string{"missing.json"}))
+}
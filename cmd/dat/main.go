@@ -8,6 +8,7 @@ func main() {
 	rootCmd := NewRootCommand()
 	rootCmd.ParseFlags()
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		PrintHints(os.Stderr, err)
+		os.Exit(ExitCode(err))
 	}
 }
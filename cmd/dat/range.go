@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Setheck/dat/pkg/dat"
+)
+
+// RangeCommand prints every instant between two times
+type RangeCommand struct {
+	cmd *cobra.Command
+
+	step         *string
+	exclusive    *bool
+	align        *bool
+	milliseconds *bool
+	tf           *bool
+	format       *string
+	zone         *string
+	local        *bool
+	utc          *bool
+}
+
+// NewRangeCommand creates a new instance of a RangeCommand
+func NewRangeCommand() *RangeCommand {
+	rc := &RangeCommand{}
+	rc.cmd = &cobra.Command{
+		Use:   "range <start> <end>",
+		Short: "print every instant from start to end",
+		Long: `range prints the instants from start to end, one per line, separated by --step.
+Start and end are epochs, times with --tf, or now. Steps take the units y, q, mo, w and d,
+which follow the calendar of the --zone (local by default), and h, m, s, ms, us and ns,
+for example 1h, 15m, 1d, 1mo or 1d12h. A negative step counts down from start to end.`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return rc.RunE(args[0], args[1])
+		},
+	}
+	return rc
+}
+
+// ParseFlags parse and assign flags
+func (r *RangeCommand) ParseFlags() {
+	flgs := r.cmd.Flags()
+	r.step = flgs.StringP("step", "s", "1h", "time between instants (ex: 15m, 1d, 1mo)")
+	r.exclusive = flgs.BoolP("exclusive", "e", false, "exclude the end")
+	r.align = flgs.Bool("align", false, "start at the first boundary of the step unit, the next hour for 1h or month for 1mo")
+	r.milliseconds = flgs.BoolP("milliseconds", "m", false, "epochs in milliseconds")
+	r.tf = flgs.BoolP("tf", "t", false, "attempt to parse start and end as a known time format")
+	r.format = flgs.StringP("format", "f", "", "https://golang.org/pkg/time/ format for time output including constant names")
+	r.zone = flgs.StringP("zone", "z", "", "display and step in a specific time zone by tz database name, offset such as +05:30 or POSIX TZ string")
+	r.local = flgs.BoolP("local", "l", false, "display the formatted time in the local timezone")
+	r.utc = flgs.BoolP("utc", "u", false, "display the formatted time in the utc timezone and step in utc")
+	_ = r.cmd.RegisterFlagCompletionFunc("zone", completeZone)
+	_ = r.cmd.RegisterFlagCompletionFunc("format", completeFormat)
+}
+
+// options are the output options of each instant
+func (r *RangeCommand) options() options {
+	return options{
+		Milliseconds: *r.milliseconds,
+		Format:       *r.format,
+		Zone:         *r.zone,
+		Local:        *r.local,
+		UTC:          *r.utc,
+	}
+}
+
+// RunE prints the range
+func (r *RangeCommand) RunE(startArg, endArg string) error {
+	step, err := dat.ParseStep(*r.step)
+	if err != nil {
+		return err
+	}
+
+	// calendar steps follow the zone that is displayed
+	loc := time.Local
+	switch {
+	case *r.zone != "":
+		if loc, err = dat.LoadZone(*r.zone); err != nil {
+			return err
+		}
+	case *r.utc:
+		loc = time.UTC
+	}
+
+	precision := dat.Seconds
+	if *r.milliseconds {
+		precision = dat.Milliseconds
+	}
+	conv := dat.NewConverter(dat.WithPrecision(precision), dat.WithTimeFormats(*r.tf))
+	var bounds [2]time.Time
+	for i, arg := range []string{startArg, endArg} {
+		if arg == "now" {
+			bounds[i] = timeNow()
+			continue
+		}
+		parsed, err := conv.Parse(arg)
+		if err != nil {
+			return err
+		}
+		bounds[i] = parsed.Time
+	}
+
+	start, end := bounds[0].In(loc), bounds[1].In(loc)
+	if *r.align {
+		start = step.Align(start)
+	}
+	opts := r.options()
+	return dat.Range(start, end, step, *r.exclusive, func(tm time.Time) error {
+		_, err := fmt.Fprint(stdOut, buildOutput(tm, opts))
+		return err
+	})
+}
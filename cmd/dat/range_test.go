@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Setheck/dat/pkg/dat"
+)
+
+func TestRangeCommand_ParseFlags(t *testing.T) {
+	rc := NewRangeCommand()
+	rc.ParseFlags()
+
+	fset := rc.cmd.Flags()
+	for _, name := range []string{"step", "exclusive", "align", "milliseconds", "tf", "format", "zone", "local", "utc"} {
+		assert.NotNil(t, fset.Lookup(name), name)
+	}
+	assert.Equal(t, "1h", *rc.step)
+}
+
+func TestRangeCommand_RunE(t *testing.T) {
+	saveStdOut := stdOut
+	saveTimeNow := timeNow
+	defer func() {
+		stdOut = saveStdOut
+		timeNow = saveTimeNow
+	}()
+	timeNow = func() time.Time {
+		return time.Unix(1700007200, 0)
+	}
+
+	tests := []struct {
+		name       string
+		start, end string
+		flags      []string
+		want       string
+		err        error
+	}{
+		{"hourly", "1700000000", "1700007200", nil,
+			"1700000000\n1700003600\n1700007200\n", nil},
+		{"exclusive", "1700000000", "1700007200", []string{"--exclusive"},
+			"1700000000\n1700003600\n", nil},
+		{"aligned", "1700000000", "1700007200", []string{"--align", "-u"},
+			"11/14/2023 23:00:00 +0000\n11/15/2023 00:00:00 +0000\n", nil},
+		{"now", "1700000000", "now", []string{"-s", "2h"},
+			"1700000000\n1700007200\n", nil},
+		{"milliseconds", "1700000000000", "1700000001000", []string{"-m", "-s", "500ms"},
+			"1700000000000\n1700000000500\n1700000001000\n", nil},
+		{"months", "2024-01-31T00:00:00Z", "2024-04-01T00:00:00Z", []string{"--tf", "-s", "1mo", "-z", "UTC", "-f", "2006-01-02"},
+			"2024-01-31\n2024-02-29\n2024-03-31\n", nil},
+		{"days in a zone", "2024-03-09T08:00:00Z", "2024-03-11T07:00:00Z", []string{"--tf", "-s", "1d", "-z", tzLosAngeles, "-f", "rfc3339"},
+			"2024-03-09T00:00:00-08:00\n2024-03-10T00:00:00-08:00\n2024-03-11T00:00:00-07:00\n", nil},
+		{"countdown", "1700007200", "1700000000", []string{"-s", "-1h", "-e"},
+			"1700007200\n1700003600\n", nil},
+		{"wrong direction", "1700007200", "1700000000", nil, "", dat.ErrStepDirection},
+		{"bad start", "asdf", "1700000000", nil, "", dat.ErrNotAnEpoch},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outputBuffer := new(bytes.Buffer)
+			stdOut = outputBuffer
+
+			rc := NewRangeCommand()
+			rc.ParseFlags()
+			assert.NoError(t, rc.cmd.Flags().Parse(test.flags))
+			err := rc.RunE(test.start, test.end)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, outputBuffer.String())
+		})
+	}
+
+	for _, flags := range [][]string{{"-s", "1x"}, {"-z", "Mars/Olympus"}} {
+		rc := NewRangeCommand()
+		rc.ParseFlags()
+		assert.NoError(t, rc.cmd.Flags().Parse(flags))
+		assert.Error(t, rc.RunE("1700000000", "1700007200"))
+	}
+}
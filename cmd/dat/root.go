@@ -1,8 +1,11 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"strconv"
 	"strings"
@@ -13,29 +16,9 @@ import (
 
 	"github.com/Setheck/dat/pkg/build"
 	"github.com/Setheck/dat/pkg/clipper"
+	"github.com/Setheck/dat/pkg/dat"
 )
 
-const DateFormat = "01/02/2006 15:04:05 -0700"
-
-var supportedTimeFormats = map[string]string{
-	// format: name
-	time.ANSIC:       "ANSIC",
-	time.UnixDate:    "UnixDate",
-	time.RubyDate:    "RubyDate",
-	time.RFC822:      "RFC822",
-	time.RFC822Z:     "RFC822Z",
-	time.RFC850:      "RFC850",
-	time.RFC1123:     "RFC1123",
-	time.RFC1123Z:    "RFC1123Z",
-	time.RFC3339:     "RFC3339",
-	time.RFC3339Nano: "RFC3339Nano",
-	time.Kitchen:     "Kitchen",
-	time.Stamp:       "Stamp",
-	time.StampMilli:  "StampMilli",
-	time.StampMicro:  "StampMicro",
-	time.StampNano:   "StampNano",
-}
-
 // CobraCommand interface for *cobra.Command
 type CobraCommand interface {
 	Execute() error
@@ -44,9 +27,15 @@ type CobraCommand interface {
 
 var _ CobraCommand = &cobra.Command{}
 
+// SubCommand is a command nested under the root command
+type SubCommand interface {
+	ParseFlags()
+}
+
 // RootCommand root cobra command
 type RootCommand struct {
-	cmd CobraCommand
+	cmd         CobraCommand
+	subCommands []SubCommand
 
 	ver          *bool
 	local        *bool
@@ -59,6 +48,32 @@ type RootCommand struct {
 	delta        *string
 	zone         *string
 	tf           *bool
+	minYear      *int
+	maxYear      *int
+	id           *string
+	snowflake    *string
+	epochBase    *string
+	outputBase   *string
+	scale        *string
+	outputScale  *string
+	leapSeconds  *string
+	radix        *int
+	bytes        *bool
+	endian       *string
+	truncate     *string
+	round        *string
+	startOf      *string
+	endOf        *string
+	weekend      *string
+	holidays     *[]string
+	output       *string
+	epochs       *bool
+	verbose      *bool
+	debug        *bool
+	strict       *bool
+	dialect      *string
+	locale       *string
+	calendar     *[]string
 }
 
 // options
@@ -74,23 +89,178 @@ type options struct {
 	Delta        string
 	Zone         string
 	Tf           bool
+	MinYear      int
+	MaxYear      int
+	ID           string
+	Snowflake    string
+	EpochBase    string
+	OutputBase   string
+	Scale        string
+	OutputScale  string
+	LeapSeconds  string
+	Radix        int
+	Bytes        bool
+	Endian       string
+	Truncate     string
+	Round        string
+	StartOf      string
+	EndOf        string
+	Weekend      string
+	Holidays     []string
+	Output       string
+	Epochs       bool
+	Verbose      bool
+	Debug        bool
+	Strict       bool
+	Dialect      string
+	Locale       string
+	Calendar     []string
 
 	detectedFormat string
+	detectedID     *dat.ID
+	leapSecond     *dat.LeapSecondNote
+	business       *dat.BusinessCalendar
+	locale         *dat.Locale
+	// offsetZones looks up the zones sharing a fixed offset zone, see dat.WithOffsetZones
+	offsetZones bool
+}
+
+// converter creates a dat.Converter configured from the options
+func (o options) converter() *dat.Converter {
+	precision := dat.Seconds
+	if o.Milliseconds {
+		precision = dat.Milliseconds
+	}
+	copts := []dat.Option{
+		dat.WithPrecision(precision),
+		dat.WithFormat(o.Format),
+		dat.WithDelta(o.Delta),
+		dat.WithZone(o.Zone),
+		dat.WithTimeFormats(o.Tf),
+	}
+	if dialect, err := dat.ParseDialect(o.Dialect); err == nil {
+		copts = append(copts, dat.WithDialect(dialect))
+	}
+	if o.locale != nil {
+		copts = append(copts, dat.WithLocale(o.locale))
+	}
+	if cals, err := o.calendars(); err == nil && len(cals) > 0 {
+		copts = append(copts, dat.WithCalendars(cals...))
+	}
+	if o.MinYear != 0 {
+		copts = append(copts, dat.WithMinYear(o.MinYear))
+	}
+	if o.MaxYear != 0 {
+		copts = append(copts, dat.WithMaxYear(o.MaxYear))
+	}
+	if o.ID != "" {
+		copts = append(copts, dat.WithID(dat.IDKind(strings.ToLower(o.ID))))
+	}
+	if ms, err := dat.ParseSnowflakeEpoch(o.Snowflake); err == nil {
+		copts = append(copts, dat.WithSnowflakeEpoch(ms))
+	}
+	if base, err := dat.ParseEpochBase(o.EpochBase); err == nil {
+		copts = append(copts, dat.WithEpochBase(base))
+	}
+	if base, err := dat.ParseEpochBase(o.OutputBase); err == nil {
+		copts = append(copts, dat.WithOutputBase(base))
+	}
+	if scale, err := dat.ParseScale(o.Scale); err == nil {
+		copts = append(copts, dat.WithScale(scale))
+	}
+	if scale, err := dat.ParseScale(o.OutputScale); err == nil {
+		copts = append(copts, dat.WithOutputScale(scale))
+	}
+	if o.Radix != 0 {
+		copts = append(copts, dat.WithRadix(o.Radix))
+	}
+	if order, err := dat.ParseByteOrder(o.Endian); err == nil && o.Bytes {
+		copts = append(copts, dat.WithBytes(order))
+	}
+	if mode, unit, err := o.snap(); err == nil && unit != "" {
+		copts = append(copts, dat.WithSnap(mode, unit))
+	}
+	if o.business != nil {
+		copts = append(copts, dat.WithBusinessCalendar(o.business))
+	}
+	if o.offsetZones {
+		copts = append(copts, dat.WithOffsetZones(true))
+	}
+	if o.Epochs {
+		copts = append(copts, dat.WithEpochs(true))
+	}
+	if o.All {
+		copts = append(copts, dat.WithBases(true), dat.WithMetadata(true))
+	}
+	return dat.NewConverter(copts...)
+}
+
+// calendars are the calendars to display, all of them for "all"
+func (o options) calendars() ([]dat.Calendar, error) {
+	return dat.ParseCalendars(o.Calendar)
+}
+
+// snap returns the snap mode and unit of the options, the unit is empty when none is set
+func (o options) snap() (dat.SnapMode, dat.Unit, error) {
+	var (
+		mode dat.SnapMode
+		name string
+	)
+	for _, s := range []struct {
+		mode dat.SnapMode
+		unit string
+	}{
+		{dat.SnapTruncate, o.Truncate},
+		{dat.SnapRound, o.Round},
+		{dat.SnapTruncate, o.StartOf},
+		{dat.SnapEnd, o.EndOf},
+	} {
+		if s.unit == "" {
+			continue
+		}
+		if name != "" {
+			return "", "", errors.New("only one of --truncate, --round, --start-of and --end-of can be set")
+		}
+		mode, name = s.mode, s.unit
+	}
+	if name == "" {
+		return "", "", nil
+	}
+	unit, err := dat.ParseUnit(name)
+	return mode, unit, err
 }
 
 // NewRootCommand creates a new instance of a RootCommand
 func NewRootCommand() *RootCommand {
 	rc := &RootCommand{}
-	rc.cmd = &cobra.Command{
-		Use: fmt.Sprint(build.Application, " [epoch]"),
+	cmd := &cobra.Command{
+		Use: fmt.Sprint(build.Application, " [epoch...]"),
 		Long: fmt.Sprint(build.Application, ` is a simple tool for converting epochs,
 when called without arguments dat returns the current epoch.
-Likewise, if an epoch is not given the current epoch is assumed.`),
+Likewise, if an epoch is not given the current epoch is assumed.
+Several epochs are converted in turn, each output after its epoch.`),
 		SilenceUsage: true, // prevent usage on error
+		Args:         cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return RunE(rc.options(), args)
 		},
 	}
+
+	// replaced by CompletionCommand
+	cmd.CompletionOptions.DisableDefaultCmd = true
+
+	serve := NewServeCommand()
+	completion := NewCompletionCommand()
+	cal := NewCalCommand()
+	rng := NewRangeCommand()
+	cron := NewCronCommand()
+	diff := NewDiffCommand()
+	csv := NewCSVCommand()
+	jsonConv := NewJSONCommand()
+	cmd.AddCommand(serve.cmd, completion.cmd, cal.cmd, rng.cmd, cron.cmd, diff.cmd, csv.cmd, jsonConv.cmd)
+	rc.subCommands = append(rc.subCommands, serve, completion, cal, rng, cron, diff, csv, jsonConv)
+
+	rc.cmd = cmd
 	return rc
 }
 
@@ -98,16 +268,49 @@ Likewise, if an epoch is not given the current epoch is assumed.`),
 func (r *RootCommand) ParseFlags() {
 	flgs := r.cmd.Flags()
 	r.ver = flgs.BoolP("version", "v", false, "print version and exit")
-	r.all = flgs.BoolP("all", "a", false, "display the epoch and formatted local and utc values of the epoch")
+	r.all = flgs.BoolP("all", "a", false, "display the epoch, formatted local and utc values, calendar metadata and bases of the epoch")
 	r.local = flgs.BoolP("local", "l", false, "display the formatted epoch in the local timezone")
 	r.utc = flgs.BoolP("utc", "u", false, "display the formatted epoch in the utc timezone")
 	r.copy = flgs.BoolP("copy", "c", false, "copy output to the clipboard")
 	r.paste = flgs.BoolP("paste", "p", false, "read input from the clipboard")
 	r.milliseconds = flgs.BoolP("milliseconds", "m", false, "epochs in milliseconds")
 	r.format = flgs.StringP("format", "f", "", "https://golang.org/pkg/time/ format for time output including constant names")
-	r.delta = flgs.StringP("delta", "d", "", "a duration in which to modify the epoch (ex:+2h3s) see https://golang.org/pkg/time/#ParseDuration")
-	r.zone = flgs.StringP("zone", "z", "", "display a specific time zone by tz database name see https://en.wikipedia.org/wiki/List_of_tz_database_time_zones")
+	r.delta = flgs.StringP("delta", "d", "", "a duration in which to modify the epoch (ex:+2h3s, or +5bd business days) see https://golang.org/pkg/time/#ParseDuration")
+	r.zone = flgs.StringP("zone", "z", "", "display a specific time zone by tz database name (see https://en.wikipedia.org/wiki/List_of_tz_database_time_zones), offset such as +05:30 or UTC-8, or POSIX TZ string")
 	r.tf = flgs.BoolP("tf", "t", false, "attempt to parse input as a known time format")
+	r.minYear = flgs.Int("min-year", 0, "reject input before this year (0 for no bound)")
+	r.maxYear = flgs.Int("max-year", 0, "reject input after this year (0 for no bound)")
+	r.id = flgs.String("id", "", "parse input as an id with an embedded time (snowflake, ulid, uuid, objectid, ksuid), snowflakes under 17 digits need it")
+	r.snowflake = flgs.String("snowflake-epoch", "twitter", "epoch of snowflake ids, twitter, discord, instagram or unix milliseconds")
+	r.epochBase = flgs.String("epoch-base", "unix", "epoch base of the input, unix, ntp, gps, filetime, ticks, excel, mac, jd or mjd")
+	r.outputBase = flgs.String("output-base", "unix", "epoch base of the output, unix, ntp, gps, filetime, ticks, excel, mac, jd or mjd")
+	r.scale = flgs.String("scale", "utc", "time scale of the input, utc, tai or gps")
+	r.outputScale = flgs.String("output-scale", "utc", "time scale of the output, utc, tai or gps")
+	r.leapSeconds = flgs.String("leap-seconds", "", "leap-seconds.list file to use when newer than the embedded table")
+	r.radix = flgs.Int("base", 0, "radix of epoch input and output, 2 to 36 (0 reads 0x, 0o and 0b prefixes)")
+	r.bytes = flgs.Bool("bytes", false, "read input as the hex bytes of a 32 or 64 bit epoch field")
+	r.endian = flgs.String("endian", "little", "byte order of --bytes input, big or little")
+	r.truncate = flgs.String("truncate", "", "snap the time down to a second, minute, hour, day, week, month, quarter or year in the zone")
+	r.round = flgs.String("round", "", "snap the time to the nearest second, minute, hour, day, week, month, quarter or year in the zone")
+	r.startOf = flgs.String("start-of", "", "the start of the time's second, minute, hour, day, week, month, quarter or year in the zone")
+	r.endOf = flgs.String("end-of", "", "the end of the time's second, minute, hour, day, week, month, quarter or year in the zone")
+	r.weekend = flgs.String("weekend", "", "weekend days of business day deltas (ex: sat,sun or fri-sat, default sat,sun)")
+	r.output = flgs.StringP("output", "o", outputText, "output format, text or json")
+	r.epochs = flgs.Bool("epochs", false, "display the epoch in seconds, milliseconds, microseconds and nanoseconds, in hex and in the configured epoch bases")
+	r.verbose = flgs.Bool("verbose", false, "trace how input is parsed and output built on stderr")
+	r.debug = flgs.Bool("debug", false, "trace as --verbose does, along with each layout tried and how input is normalized")
+	r.dialect = flgs.String("format-dialect", "go", "dialect of --format, go, strftime (%Y-%m-%d), java (yyyy-MM-dd) or moment (YYYY-MM-DD)")
+	r.locale = flgs.String("locale", "", "locale of month and weekday names and the default layout, such as fr_FR, or env for LC_ALL, LC_TIME or LANG")
+	r.calendar = flgs.StringSlice("calendar", nil, "also display the date in calendars: iso, islamic, hebrew, persian, japanese, buddhist, chinese or all")
+	r.strict = flgs.Bool("strict", false, "fail instead of falling back when the format has no layout elements, the delta is not a duration or the zone cannot be loaded")
+	r.holidays = flgs.StringSlice("holidays", nil, "iCalendar (.ics) or YAML (.yaml) files of holidays that business day deltas skip")
+
+	if cmd, ok := r.cmd.(*cobra.Command); ok {
+		registerCompletions(cmd)
+	}
+	for _, sub := range r.subCommands {
+		sub.ParseFlags()
+	}
 }
 
 // options retrieves command input options
@@ -124,6 +327,32 @@ func (r *RootCommand) options() options {
 		Delta:        *r.delta,
 		Zone:         *r.zone,
 		Tf:           *r.tf,
+		MinYear:      *r.minYear,
+		MaxYear:      *r.maxYear,
+		ID:           *r.id,
+		Snowflake:    *r.snowflake,
+		EpochBase:    *r.epochBase,
+		OutputBase:   *r.outputBase,
+		Scale:        *r.scale,
+		OutputScale:  *r.outputScale,
+		LeapSeconds:  *r.leapSeconds,
+		Radix:        *r.radix,
+		Bytes:        *r.bytes,
+		Endian:       *r.endian,
+		Truncate:     *r.truncate,
+		Round:        *r.round,
+		StartOf:      *r.startOf,
+		EndOf:        *r.endOf,
+		Weekend:      *r.weekend,
+		Holidays:     *r.holidays,
+		Output:       *r.output,
+		Epochs:       *r.epochs,
+		Verbose:      *r.verbose,
+		Debug:        *r.debug,
+		Strict:       *r.strict,
+		Dialect:      *r.dialect,
+		Locale:       *r.locale,
+		Calendar:     *r.calendar,
 	}
 }
 
@@ -133,7 +362,9 @@ func (r *RootCommand) Execute() error {
 }
 
 // test points
+var stdIn io.Reader = os.Stdin
 var stdOut io.Writer = os.Stdout
+var stdErr io.Writer = os.Stderr
 var buildOutput = BuildOutput
 
 var timeNow = time.Now
@@ -155,197 +386,554 @@ func RunE(opts options, args []string) (err error) {
 		return nil
 	}
 
-	// default to now
-	var epocInt int64
-	epoch := timeNow()
-	if opts.Milliseconds {
-		epocInt = epoch.UnixMilli()
-	} else {
-		epocInt = epoch.Unix()
+	if logger := newLogger(opts); logger != nil {
+		dat.SetLogger(logger)
+		defer dat.SetLogger(nil)
 	}
-	epochstr := strconv.FormatInt(epocInt, 10)
 
-	// take value passed in
-	if len(args) > 0 {
-		epochstr = args[0]
+	if opts.Output != "" && opts.Output != outputText && opts.Output != outputJSON {
+		return fmt.Errorf("unknown output %q, expected %s or %s", opts.Output, outputText, outputJSON)
 	}
-
-	// paste mode reads from the clipboard
-	if opts.Paste {
-		var err error
-		epochstr, err = clipper.ClipboardHelper.ReadAll()
-		if err != nil {
+	if opts.Snowflake != "" {
+		if _, err := dat.ParseSnowflakeEpoch(opts.Snowflake); err != nil {
 			return err
 		}
 	}
-
-	var (
-		tm     time.Time
-		format string
-	)
-	if opts.Tf {
-		tm, format, err = ParseTime(epochstr)
+	for _, base := range []string{opts.EpochBase, opts.OutputBase} {
+		if _, err := dat.ParseEpochBase(base); err != nil {
+			return err
+		}
+	}
+	for _, scale := range []string{opts.Scale, opts.OutputScale} {
+		if _, err := dat.ParseScale(scale); err != nil {
+			return err
+		}
+	}
+	if _, _, err := opts.snap(); err != nil {
+		return err
+	}
+	if err := dat.ValidRadix(opts.Radix); err != nil {
+		return err
+	}
+	dialect, err := dat.ParseDialect(opts.Dialect)
+	if err != nil {
+		return err
+	}
+	if err := checkFallbacks(opts, dialect); err != nil {
+		return err
+	}
+	if strings.EqualFold(opts.Locale, localeEnv) {
+		opts.Locale = envLocale()
+	}
+	if opts.locale, err = dat.LookupLocale(opts.Locale); err != nil {
+		return err
+	}
+	if opts.locale != nil {
+		dat.Logger().Info("locale", "locale", opts.Locale, "language", opts.locale.Name)
+	}
+	if _, err := opts.calendars(); err != nil {
+		return err
+	}
+	if opts.Bytes {
+		if _, err := dat.ParseByteOrder(opts.Endian); err != nil {
+			return err
+		}
+	}
+	if opts.LeapSeconds != "" {
+		used, err := dat.LoadLeapSeconds(opts.LeapSeconds)
 		if err != nil {
 			return err
 		}
-		if name, ok := supportedTimeFormats[format]; ok {
-			opts.detectedFormat = name
+		if !used {
+			fmt.Fprintln(stdErr, "warning:", opts.LeapSeconds, "does not expire after the embedded leap second table, ignoring it")
 		} else {
-			fmt.Println("failed to detect format.")
+			dat.Logger().Info("leap second table loaded", "path", opts.LeapSeconds)
 		}
-	} else {
-		// validate and convert to time
-		tm, err = ParseEpochTime(epochstr, opts.Milliseconds)
+	}
+	if opts.Weekend != "" || len(opts.Holidays) > 0 {
+		if opts.business, err = businessCalendar(opts.Weekend, opts.Holidays); err != nil {
+			return err
+		}
+		dat.Logger().Info("business calendar", "weekend", opts.business.Weekend(), "holidays", opts.Holidays)
+	}
+	// the zones that share a fixed offset are only looked up when the zone is shown
+	opts.offsetZones = opts.Output == outputJSON || opts.All || opts.Local || opts.UTC || opts.Format == ""
+	conv := opts.converter()
+
+	// take the values passed in, paste mode reads from the clipboard
+	inputs := args
+	if opts.Paste {
+		input, err := clipper.ClipboardHelper.ReadAll()
 		if err != nil {
 			return err
 		}
+		inputs = []string{input}
+		dat.Logger().Info("input read from the clipboard", "input", input)
 	}
 
-	output := buildOutput(tm, opts)
+	// validate and convert to time, defaulting to now in the precision of the epoch
+	var conversions []conversion
+	if len(inputs) == 0 {
+		unit := time.Second
+		if opts.Milliseconds {
+			unit = time.Millisecond
+		}
+		now := timeNow().Truncate(unit)
+		dat.Logger().Info("no input, converting now", "instant", now)
+		conversions = append(conversions, conversion{input: "now", parsed: &dat.Parsed{Time: now}})
+	}
+	for _, input := range inputs {
+		c := conversion{input: input}
+		c.parsed, c.err = conv.Parse(input)
+		conversions = append(conversions, c)
+	}
+	grouped := len(conversions) > 1
+	if !grouped && conversions[0].err != nil {
+		return conversions[0].err
+	}
+	for _, c := range conversions {
+		if c.err == nil {
+			noteInput(conv, opts, c, grouped)
+		}
+	}
+
+	var output string
+	if opts.Output == outputJSON {
+		if output, err = jsonOutput(conv, conversions, grouped); err != nil {
+			return err
+		}
+	} else {
+		output = textOutput(conversions, opts, grouped)
+	}
 	if opts.Copy {
 		if err := clipper.ClipboardHelper.WriteAll(strings.TrimSpace(output)); err != nil {
 			return err
 		}
 	}
 
-	_, err = fmt.Fprint(stdOut, output)
-	return err
+	if _, err = fmt.Fprint(stdOut, output); err != nil {
+		return err
+	}
+	return failures(conversions)
 }
 
-// BuildOutput returns the output of the time for the given options
-func BuildOutput(tm time.Time, opts options) string {
+// envLocale is the locale of LC_ALL, LC_TIME or LANG, the first that is set,
+// when dat has its names. It is empty for C, POSIX and unknown locales.
+func envLocale() string {
+	for _, key := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		name := os.Getenv(key)
+		if name == "" {
+			continue
+		}
+		if l, err := dat.LookupLocale(name); err != nil || l == nil {
+			return ""
+		}
+		return name
+	}
+	return ""
+}
+
+// checkFallbacks checks the output options that fall back when they are invalid, a format
+// without layout elements, a delta that is not a duration and a zone that cannot be loaded.
+// They fail with --strict and are warnings otherwise.
+func checkFallbacks(opts options, dialect dat.Dialect) error {
+	type fallback struct {
+		err     error
+		instead string
+	}
+	var fallbacks []fallback
+	if opts.Format != "" {
+		layout, err := dat.TranslateLayout(opts.Format, dialect)
+		if err == nil {
+			err = dat.ValidateLayout(layout)
+		}
+		var pe *dat.ParseError
+		if errors.As(err, &pe) && dialect == dat.DialectGo && dat.LooksLikeJava(opts.Format) {
+			pe.Suggestions = append(pe.Suggestions, "or use --format-dialect java")
+		}
+		fallbacks = append(fallbacks, fallback{err, "using the default format"})
+	}
+	if _, business := dat.ParseBusinessDelta(opts.Delta); opts.Delta != "" && !business {
+		if _, err := time.ParseDuration(opts.Delta); err != nil {
+			fallbacks = append(fallbacks, fallback{fmt.Errorf("invalid delta: %w", err), "ignoring it"})
+		}
+	}
+	if opts.Zone != "" {
+		if _, err := dat.LoadZone(opts.Zone); err != nil {
+			fallbacks = append(fallbacks, fallback{fmt.Errorf("invalid zone: %w", err), "ignoring it"})
+		}
+	}
+	for _, f := range fallbacks {
+		if f.err == nil {
+			continue
+		}
+		if opts.Strict {
+			return f.err
+		}
+		fmt.Fprintf(stdErr, "warning: %s, %s\n", f.err, f.instead)
+		PrintHints(stdErr, f.err)
+	}
+	return nil
+}
+
+// newLogger is the logger tracing --verbose and --debug on stderr, nil when neither is given
+func newLogger(opts options) *slog.Logger {
+	level := slog.LevelInfo
+	switch {
+	case opts.Debug:
+		level = slog.LevelDebug
+	case !opts.Verbose:
+		return nil
+	}
+	return slog.New(slog.NewTextHandler(stdErr, &slog.HandlerOptions{
+		Level: level,
+		// the time of each record is noise in a trace of one run
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+// output formats
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// localeEnv is the --locale that reads the locale from the environment, see envLocale
+const localeEnv = "env"
+
+// conversion is an input and its time, or why it could not be converted
+type conversion struct {
+	input  string
+	parsed *dat.Parsed
+	err    error
+}
+
+// noteInput writes leap second notes, detected snowflakes and warnings about a parsed input to stderr,
+// naming the input when there are several
+func noteInput(conv *dat.Converter, opts options, c conversion, grouped bool) {
+	subject, prefix := "input", ""
+	if grouped {
+		subject, prefix = c.input, c.input+": "
+	}
+	if c.parsed.LeapSecond != nil {
+		fmt.Fprintln(stdErr, "note:", subject, "is", c.parsed.LeapSecond)
+	}
+	if c.parsed.ID != nil && c.parsed.ID.Kind == dat.IDSnowflake && opts.ID == "" {
+		fmt.Fprintln(stdErr, "note:", subject, "is too long for an epoch, read as a snowflake id")
+	}
+	if c.parsed.ID == nil {
+		for _, w := range conv.Check(c.parsed.Time) {
+			fmt.Fprintln(stdErr, "warning:", prefix+warningText(w))
+		}
+	}
+}
+
+// textOutput builds the output of each conversion, grouped under its input when there
+// are several, with a summary for --all. Failures are written to stderr.
+func textOutput(conversions []conversion, opts options, grouped bool) string {
 	output := ""
+	for _, c := range conversions {
+		if c.err != nil {
+			fmt.Fprintln(stdErr, "error:", c.input+":", c.err)
+			PrintHints(stdErr, c.err)
+			continue
+		}
+		o := opts
+		o.detectedFormat = c.parsed.DetectedFormat
+		o.detectedID = c.parsed.ID
+		o.leapSecond = c.parsed.LeapSecond
+		out := buildOutput(c.parsed.Time, o)
+		if grouped {
+			out = groupOutput(c.input, out)
+		}
+		output += out
+	}
+	if grouped && opts.All {
+		output += summary(conversions)
+	}
+	return output
+}
 
-	// add delta if applicable.
-	if opts.Delta != "" {
-		tm = AddDelta(tm, opts.Delta)
+// groupOutput puts single line output after its input, and indents longer output under it
+func groupOutput(input, output string) string {
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) == 1 {
+		return fmt.Sprintln(input+":", lines[0])
+	}
+	grouped := fmt.Sprintln(input + ":")
+	for _, line := range lines {
+		grouped += fmt.Sprintln(" ", line)
 	}
+	return grouped
+}
 
-	var intTime int64
-	if opts.Milliseconds {
-		intTime = tm.UnixMilli()
-	} else {
-		intTime = tm.Unix()
+// summary counts the conversions and spans the times of those that succeeded
+func summary(conversions []conversion) string {
+	var earliest, latest *conversion
+	failed := 0
+	for i, c := range conversions {
+		switch {
+		case c.err != nil:
+			failed++
+			continue
+		case earliest == nil:
+			earliest, latest = &conversions[i], &conversions[i]
+		case c.parsed.Time.Before(earliest.parsed.Time):
+			earliest = &conversions[i]
+		case c.parsed.Time.After(latest.parsed.Time):
+			latest = &conversions[i]
+		}
 	}
+	output := fmt.Sprintln("summary:")
+	output += fmt.Sprintf("  inputs: %d\n", len(conversions))
+	output += fmt.Sprintf("  failed: %d\n", failed)
+	if earliest != nil {
+		output += fmt.Sprintf("  earliest: %s\n", earliest.input)
+		output += fmt.Sprintf("  latest: %s\n", latest.input)
+		output += fmt.Sprintf("  span: %s\n", latest.parsed.Time.Sub(earliest.parsed.Time))
+	}
+	return output
+}
 
-	outFormat := DateFormat
-	if opts.Format != "" {
-		outFormat = opts.Format
+// jsonItem is the json output of one of several inputs
+type jsonItem struct {
+	Value  string      `json:"value"`
+	Result *dat.Result `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// jsonOutput is the result of a single conversion, or an array of items for several
+func jsonOutput(conv *dat.Converter, conversions []conversion, grouped bool) (string, error) {
+	var v interface{}
+	if !grouped {
+		v = conv.Result(conversions[0].parsed)
+	} else {
+		items := make([]jsonItem, 0, len(conversions))
+		for _, c := range conversions {
+			item := jsonItem{Value: c.input}
+			if c.err != nil {
+				item.Error = c.err.Error()
+			} else {
+				item.Result = conv.Result(c.parsed)
+			}
+			items = append(items, item)
+		}
+		v = items
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
 	}
+	return string(b) + "\n", nil
+}
 
-	var formattedZone string
-	if opts.Zone != "" {
-		loc, err := time.LoadLocation(opts.Zone)
+// inputsError is the failure of some of several inputs, it is the kind of the first
+// failure so the exit code matches it
+type inputsError struct {
+	failed, total int
+	first         error
+}
+
+// Error implements error
+func (e *inputsError) Error() string {
+	return fmt.Sprintf("%d of %d inputs could not be converted", e.failed, e.total)
+}
+
+// Is reports whether the first failure is target
+func (e *inputsError) Is(target error) bool {
+	return errors.Is(e.first, target)
+}
+
+// failures returns an inputsError when any conversion failed
+func failures(conversions []conversion) error {
+	var err *inputsError
+	for _, c := range conversions {
+		if c.err == nil {
+			continue
+		}
 		if err == nil {
-			formattedZone = FormatOutput(tm.In(loc), outFormat)
+			err = &inputsError{total: len(conversions), first: c.err}
 		}
+		err.failed++
 	}
+	if err == nil {
+		return nil
+	}
+	return err
+}
+
+// warningText adds the flag to use for warnings that have one
+func warningText(w dat.Warning) string {
+	switch w {
+	case dat.WarnLooksLikeMilliseconds:
+		return string(w) + ", did you mean -m?"
+	case dat.WarnLooksLikeSeconds:
+		return string(w) + ", did you mean to omit -m?"
+	}
+	return string(w)
+}
+
+// BuildOutput returns the output of the time for the given options
+func BuildOutput(tm time.Time, opts options) string {
+	output := ""
+
+	res := opts.converter().At(tm)
+	formattedZone := res.Zone
 
 	switch {
 	case opts.All:
 		if opts.detectedFormat != "" {
 			output += fmt.Sprintln("detected:", opts.detectedFormat)
 		}
-		output += fmt.Sprintln("epoch:", intTime)
+		if opts.detectedID != nil {
+			output += fmt.Sprintln("id:", opts.detectedID.Kind)
+			for _, c := range opts.detectedID.Components {
+				output += fmt.Sprintf("  %s: %s\n", c.Name, c.Value)
+			}
+		}
+		output += fmt.Sprintln("epoch:", epochText(res))
+		if res.Scale != "" {
+			output += fmt.Sprintln("scale:", res.Scale)
+		}
+		if leap := leapSecondNote(res, opts); leap != nil {
+			output += fmt.Sprintln(" leap:", leap)
+		}
 		fallthrough
 	case opts.Local && opts.UTC:
-		output += fmt.Sprintln("local:", FormatOutput(tm.Local(), outFormat))
-		output += fmt.Sprintln("  utc:", FormatOutput(tm.UTC(), outFormat))
+		output += fmt.Sprintln("local:", res.Local)
+		output += fmt.Sprintln("  utc:", res.UTC)
 		if formattedZone != "" {
-			output += fmt.Sprintln(" zone:", formattedZone)
+			output += zoneText(res)
 		}
 
 	case opts.Local && formattedZone != "":
-		output += fmt.Sprintln("local:", FormatOutput(tm.Local(), outFormat))
-		output += fmt.Sprintln(" zone:", formattedZone)
+		output += fmt.Sprintln("local:", res.Local)
+		output += zoneText(res)
 
 	case opts.UTC && formattedZone != "":
-		output += fmt.Sprintln("  utc:", FormatOutput(tm.UTC(), outFormat))
-		output += fmt.Sprintln(" zone:", formattedZone)
+		output += fmt.Sprintln("  utc:", res.UTC)
+		output += zoneText(res)
 
 	default:
-		out := strconv.FormatInt(intTime, 10)
+		out := epochText(res)
+		if res.BaseValue != "" {
+			out = res.BaseValue
+		}
 		if opts.Local {
-			out = FormatOutput(tm.Local(), outFormat)
+			out = res.Local
 		} else if opts.UTC {
-			out = FormatOutput(tm.UTC(), outFormat)
+			out = res.UTC
 		} else if opts.Format != "" {
-			out = FormatOutput(tm, outFormat)
+			out = res.Formatted
 		} else if formattedZone != "" {
 			out = formattedZone
 		}
 		output = fmt.Sprintln(out)
+		if out == formattedZone {
+			output += sameText(res)
+		}
+	}
+
+	if len(res.Epochs) > 0 {
+		output += FormatEpochs(res)
+	}
+	if len(res.Calendars) > 0 {
+		output += FormatCalendars(res.Calendars)
+	}
+	if opts.All {
+		output += FormatMetadata(res)
+		output += FormatBases(res.Bases)
 	}
 
 	return output
 }
 
-// AddDelta simply adds the given duration if it is valid to the given time, ignores otherwise.
-func AddDelta(tm time.Time, delta string) time.Time {
-	dur, err := time.ParseDuration(delta)
-	if err == nil {
-		tm = tm.Add(dur)
-	}
-	return tm
+// zoneText is the zone line, followed by the tz database zones that share a fixed offset
+func zoneText(res *dat.Result) string {
+	return fmt.Sprintln(" zone:", res.Zone) + sameText(res)
 }
 
-// FormatOutput parses the provided time against the provided format string.
-// replacing named constants with the expected format.
-func FormatOutput(tm time.Time, outFmtS string) string {
-	outFmt := outFmtS
-	lowerFmt := strings.ToLower(outFmtS)
-	for format, name := range supportedTimeFormats {
-		if strings.ToLower(name) == lowerFmt {
-			outFmt = format
-			break
-		}
-	}
-	formattedTime := tm.Format(outFmt)
-	if formattedTime == outFmt {
-		outFmt = DateFormat
+// sameText is the line of the tz database zones that currently share a fixed offset, if any
+func sameText(res *dat.Result) string {
+	if len(res.OffsetZones) == 0 {
+		return ""
 	}
+	return fmt.Sprintln(" same:", strings.Join(res.OffsetZones, ", "))
+}
 
-	return tm.Format(outFmt)
+// epochText is the epoch of the result in its radix
+func epochText(res *dat.Result) string {
+	if res.RadixValue != "" {
+		return res.RadixValue
+	}
+	return strconv.FormatInt(res.Epoch, 10)
 }
 
-// ParseEpochTime tries to parse the string as an int, then converts to a time.Time
-func ParseEpochTime(str string, milliseconds bool) (time.Time, error) {
-	epoch, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64)
-	if err != nil {
-		return time.Time{}, fmt.Errorf("%q is not a valid epoch", TruncateString(str, 20))
+// leapSecondNote is the leap second near the output, keeping whether the input was read on it
+func leapSecondNote(res *dat.Result, opts options) *dat.LeapSecondNote {
+	if opts.leapSecond != nil && opts.leapSecond.On && opts.Delta == "" {
+		return opts.leapSecond
 	}
+	return res.LeapSecond
+}
 
-	if milliseconds {
-		return time.Unix(0, epoch*int64(time.Millisecond)), nil
+// FormatMetadata describes the time in each displayed zone, with the unix epoch in seconds
+// and milliseconds whatever the precision, see dat.WithMetadata
+func FormatMetadata(res *dat.Result) string {
+	m := res.Metadata
+	if m == nil {
+		return ""
 	}
+	output := fmt.Sprintln("metadata:")
+	output += fmt.Sprintf("  unix: %d\n", m.Unix)
+	output += fmt.Sprintf("  unix ms: %d\n", m.UnixMilli)
+	output += fmt.Sprintln("  local:", m.Local)
+	output += fmt.Sprintln("  utc:", m.UTC)
+	if m.Zone != nil {
+		output += fmt.Sprintln("  zone:", *m.Zone)
+	}
+	return output
+}
 
-	return time.Unix(epoch, 0), nil
+// FormatEpochs lists the time as a unix epoch in every precision with its hex, see dat.WithEpochs,
+// then in the radix and epoch bases that are configured
+func FormatEpochs(res *dat.Result) string {
+	output := fmt.Sprintln("epochs:")
+	for _, e := range res.Epochs {
+		output += fmt.Sprintf("  %s: %s (%s)\n", e.Unit, e.Value, e.Hex)
+	}
+	if res.Radix != 0 && res.Radix != 16 {
+		output += fmt.Sprintf("  base %d: %s\n", res.Radix, res.RadixValue)
+	}
+	for _, b := range res.EpochBases {
+		output += fmt.Sprintf("  %s: %s\n", b.Base, b.Value)
+	}
+	return output
 }
 
-func ParseTime(str string) (time.Time, string, error) {
-	for format := range supportedTimeFormats {
-		tm, err := time.Parse(format, str)
-		if err == nil {
-			return tm, format, nil
+// FormatCalendars lists the date in each calendar, or why it could not be computed
+func FormatCalendars(dates []dat.CalendarDate) string {
+	output := fmt.Sprintln("calendars:")
+	for _, d := range dates {
+		text := d.Text
+		if d.Error != "" {
+			text = "error: " + d.Error
 		}
+		output += fmt.Sprintf("  %s: %s\n", d.Calendar, text)
 	}
-	return time.Unix(0, 0), "", fmt.Errorf("invalid time format")
+	return output
 }
 
-// TruncateString reduces the size of str to the given size.
-// if str is truncated, it will include a trailing ellipsis.
-func TruncateString(str string, size int) string {
-	if size < 0 {
-		size = 0
+// FormatBases lists the time in each epoch base
+func FormatBases(bases []dat.BaseValue) string {
+	output := fmt.Sprintln("bases:")
+	for _, b := range bases {
+		output += fmt.Sprintf("  %s: %s\n", b.Base, b.Value)
 	}
-	length := len(str)
-	postfix := ""
-	if length > size {
-		if length > 3 {
-			postfix = "..."
-			size -= 3
-		}
-		return str[0:size] + postfix
-	}
-	return str
+	return output
 }
@@ -2,15 +2,19 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/Setheck/dat/pkg/clipper"
+	"github.com/Setheck/dat/pkg/dat"
 	"github.com/Setheck/dat/pkg/mocks"
 )
 
@@ -22,7 +26,7 @@ func TestNewRootCommand(t *testing.T) {
 	rc := NewRootCommand()
 	assert.NotNil(t, rc)
 	assert.NotNil(t, rc.cmd)
-
+	assert.Len(t, rc.subCommands, 8)
 }
 
 func TestRootCommand_ParseFlags(t *testing.T) {
@@ -68,6 +72,41 @@ func TestRootCommand_ParseFlags(t *testing.T) {
 	// zone
 	assert.NotNil(t, fset.ShorthandLookup("z"))
 	assert.NotNil(t, fset.Lookup("zone"))
+
+	// ids
+	assert.NotNil(t, fset.Lookup("id"))
+	assert.NotNil(t, fset.Lookup("snowflake-epoch"))
+
+	// epoch bases
+	assert.NotNil(t, fset.Lookup("epoch-base"))
+	assert.NotNil(t, fset.Lookup("output-base"))
+	assert.NotNil(t, fset.Lookup("scale"))
+	assert.NotNil(t, fset.Lookup("output-scale"))
+	assert.NotNil(t, fset.Lookup("leap-seconds"))
+	assert.NotNil(t, fset.Lookup("base"))
+	assert.NotNil(t, fset.Lookup("bytes"))
+	assert.NotNil(t, fset.Lookup("endian"))
+	assert.NotNil(t, fset.Lookup("truncate"))
+	assert.NotNil(t, fset.Lookup("round"))
+	assert.NotNil(t, fset.Lookup("start-of"))
+	assert.NotNil(t, fset.Lookup("end-of"))
+	assert.NotNil(t, fset.Lookup("weekend"))
+	assert.NotNil(t, fset.Lookup("holidays"))
+
+	// output
+	assert.NotNil(t, fset.ShorthandLookup("o"))
+	assert.NotNil(t, fset.Lookup("output"))
+	assert.NotNil(t, fset.Lookup("epochs"))
+	assert.NotNil(t, fset.Lookup("verbose"))
+	assert.NotNil(t, fset.Lookup("debug"))
+	assert.NotNil(t, fset.Lookup("strict"))
+	assert.NotNil(t, fset.Lookup("format-dialect"))
+	assert.NotNil(t, fset.Lookup("locale"))
+	assert.NotNil(t, fset.Lookup("calendar"))
+
+	// year bounds
+	assert.NotNil(t, fset.Lookup("min-year"))
+	assert.NotNil(t, fset.Lookup("max-year"))
 }
 
 func TestRootCommand_Options(t *testing.T) {
@@ -92,6 +131,32 @@ func TestRootCommand_Options(t *testing.T) {
 				delta:        StfPtr(t, ""),
 				zone:         StfPtr(t, ""),
 				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
 			},
 			options{Version: truePtr}},
 		{"copy flag",
@@ -107,6 +172,32 @@ func TestRootCommand_Options(t *testing.T) {
 				delta:        StfPtr(t, ""),
 				zone:         StfPtr(t, ""),
 				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
 			},
 			options{Copy: truePtr}},
 		{"paste flag",
@@ -122,6 +213,32 @@ func TestRootCommand_Options(t *testing.T) {
 				delta:        StfPtr(t, ""),
 				zone:         StfPtr(t, ""),
 				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
 			},
 			options{Paste: truePtr}},
 		{"all flag",
@@ -137,6 +254,32 @@ func TestRootCommand_Options(t *testing.T) {
 				delta:        StfPtr(t, ""),
 				zone:         StfPtr(t, ""),
 				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
 			},
 			options{All: truePtr}},
 		{"local flag",
@@ -152,6 +295,32 @@ func TestRootCommand_Options(t *testing.T) {
 				delta:        StfPtr(t, ""),
 				zone:         StfPtr(t, ""),
 				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
 			},
 			options{Local: truePtr}},
 		{"utc flag",
@@ -167,6 +336,32 @@ func TestRootCommand_Options(t *testing.T) {
 				delta:        StfPtr(t, ""),
 				zone:         StfPtr(t, ""),
 				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
 			},
 			options{UTC: truePtr}},
 		{"m flag",
@@ -182,6 +377,32 @@ func TestRootCommand_Options(t *testing.T) {
 				delta:        StfPtr(t, ""),
 				zone:         StfPtr(t, ""),
 				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
 			},
 			options{Milliseconds: truePtr}},
 		{"f flag",
@@ -197,6 +418,32 @@ func TestRootCommand_Options(t *testing.T) {
 				delta:        StfPtr(t, ""),
 				zone:         StfPtr(t, ""),
 				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
 			},
 			options{Format: time.RFC3339}},
 		{"d flag",
@@ -212,6 +459,32 @@ func TestRootCommand_Options(t *testing.T) {
 				delta:        StfPtr(t, "360h10m"),
 				zone:         StfPtr(t, ""),
 				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
 			},
 			options{Delta: "360h10m"}},
 		{"z flag",
@@ -227,6 +500,32 @@ func TestRootCommand_Options(t *testing.T) {
 				delta:        StfPtr(t, ""),
 				zone:         StfPtr(t, "America/Los_Angeles"),
 				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
 			},
 			options{Zone: "America/Los_Angeles"}},
 		{"detect format (tf)",
@@ -242,8 +541,608 @@ func TestRootCommand_Options(t *testing.T) {
 				delta:        StfPtr(t, ""),
 				zone:         StfPtr(t, ""),
 				tf:           &truePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
 			},
 			options{Tf: true}},
+		{"year bounds",
+			&RootCommand{
+				ver:          &falsePtr,
+				local:        &falsePtr,
+				utc:          &falsePtr,
+				all:          &falsePtr,
+				copy:         &falsePtr,
+				paste:        &falsePtr,
+				milliseconds: &falsePtr,
+				format:       StfPtr(t, ""),
+				delta:        StfPtr(t, ""),
+				zone:         StfPtr(t, ""),
+				tf:           &falsePtr,
+				minYear:      IntPtr(t, 1990),
+				maxYear:      IntPtr(t, 2100),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
+			},
+			options{MinYear: 1990, MaxYear: 2100}},
+		{"id flags",
+			&RootCommand{
+				ver:          &falsePtr,
+				local:        &falsePtr,
+				utc:          &falsePtr,
+				all:          &falsePtr,
+				copy:         &falsePtr,
+				paste:        &falsePtr,
+				milliseconds: &falsePtr,
+				format:       StfPtr(t, ""),
+				delta:        StfPtr(t, ""),
+				zone:         StfPtr(t, ""),
+				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, "snowflake"),
+				snowflake:    StfPtr(t, "discord"),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
+			},
+			options{ID: "snowflake", Snowflake: "discord"}},
+		{"epoch base flags",
+			&RootCommand{
+				ver:          &falsePtr,
+				local:        &falsePtr,
+				utc:          &falsePtr,
+				all:          &falsePtr,
+				copy:         &falsePtr,
+				paste:        &falsePtr,
+				milliseconds: &falsePtr,
+				format:       StfPtr(t, ""),
+				delta:        StfPtr(t, ""),
+				zone:         StfPtr(t, ""),
+				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, "ntp"),
+				outputBase:   StfPtr(t, "excel"),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
+			},
+			options{EpochBase: "ntp", OutputBase: "excel"}},
+		{"scale flags",
+			&RootCommand{
+				ver:          &falsePtr,
+				local:        &falsePtr,
+				utc:          &falsePtr,
+				all:          &falsePtr,
+				copy:         &falsePtr,
+				paste:        &falsePtr,
+				milliseconds: &falsePtr,
+				format:       StfPtr(t, ""),
+				delta:        StfPtr(t, ""),
+				zone:         StfPtr(t, ""),
+				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, "tai"),
+				outputScale:  StfPtr(t, "gps"),
+				leapSeconds:  StfPtr(t, "leap-seconds.list"),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
+			},
+			options{Scale: "tai", OutputScale: "gps", LeapSeconds: "leap-seconds.list"}},
+		{"radix flags",
+			&RootCommand{
+				ver:          &falsePtr,
+				local:        &falsePtr,
+				utc:          &falsePtr,
+				all:          &falsePtr,
+				copy:         &falsePtr,
+				paste:        &falsePtr,
+				milliseconds: &falsePtr,
+				format:       StfPtr(t, ""),
+				delta:        StfPtr(t, ""),
+				zone:         StfPtr(t, ""),
+				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 16),
+				bytes:        &truePtr,
+				endian:       StfPtr(t, "big"),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
+			},
+			options{Radix: 16, Bytes: true, Endian: "big"}},
+		{"snap flags",
+			&RootCommand{
+				ver:          &falsePtr,
+				local:        &falsePtr,
+				utc:          &falsePtr,
+				all:          &falsePtr,
+				copy:         &falsePtr,
+				paste:        &falsePtr,
+				milliseconds: &falsePtr,
+				format:       StfPtr(t, ""),
+				delta:        StfPtr(t, ""),
+				zone:         StfPtr(t, ""),
+				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, "hour"),
+				round:        StfPtr(t, "day"),
+				startOf:      StfPtr(t, "month"),
+				endOf:        StfPtr(t, "year"),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
+			},
+			options{Truncate: "hour", Round: "day", StartOf: "month", EndOf: "year"}},
+		{"business day flags",
+			&RootCommand{
+				ver:          &falsePtr,
+				local:        &falsePtr,
+				utc:          &falsePtr,
+				all:          &falsePtr,
+				copy:         &falsePtr,
+				paste:        &falsePtr,
+				milliseconds: &falsePtr,
+				format:       StfPtr(t, ""),
+				delta:        StfPtr(t, ""),
+				zone:         StfPtr(t, ""),
+				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, "fri-sat"),
+				holidays:     SlicePtr(t, "holidays.ics"),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
+			},
+			options{Weekend: "fri-sat", Holidays: []string{"holidays.ics"}}},
+		{"output flag",
+			&RootCommand{
+				ver:          &falsePtr,
+				local:        &falsePtr,
+				utc:          &falsePtr,
+				all:          &falsePtr,
+				copy:         &falsePtr,
+				paste:        &falsePtr,
+				milliseconds: &falsePtr,
+				format:       StfPtr(t, ""),
+				delta:        StfPtr(t, ""),
+				zone:         StfPtr(t, ""),
+				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, "json"),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
+			},
+			options{Output: "json"}},
+		{"epochs flag",
+			&RootCommand{
+				ver:          &falsePtr,
+				local:        &falsePtr,
+				utc:          &falsePtr,
+				all:          &falsePtr,
+				copy:         &falsePtr,
+				paste:        &falsePtr,
+				milliseconds: &falsePtr,
+				format:       StfPtr(t, ""),
+				delta:        StfPtr(t, ""),
+				zone:         StfPtr(t, ""),
+				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &truePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
+			},
+			options{Epochs: true}},
+		{"trace flags",
+			&RootCommand{
+				ver:          &falsePtr,
+				local:        &falsePtr,
+				utc:          &falsePtr,
+				all:          &falsePtr,
+				copy:         &falsePtr,
+				paste:        &falsePtr,
+				milliseconds: &falsePtr,
+				format:       StfPtr(t, ""),
+				delta:        StfPtr(t, ""),
+				zone:         StfPtr(t, ""),
+				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &truePtr,
+				debug:        &truePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
+			},
+			options{Verbose: true, Debug: true}},
+		{"strict flag",
+			&RootCommand{
+				ver:          &falsePtr,
+				local:        &falsePtr,
+				utc:          &falsePtr,
+				all:          &falsePtr,
+				copy:         &falsePtr,
+				paste:        &falsePtr,
+				milliseconds: &falsePtr,
+				format:       StfPtr(t, ""),
+				delta:        StfPtr(t, ""),
+				zone:         StfPtr(t, ""),
+				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &truePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
+			},
+			options{Strict: true}},
+		{"format dialect flag",
+			&RootCommand{
+				ver:          &falsePtr,
+				local:        &falsePtr,
+				utc:          &falsePtr,
+				all:          &falsePtr,
+				copy:         &falsePtr,
+				paste:        &falsePtr,
+				milliseconds: &falsePtr,
+				format:       StfPtr(t, ""),
+				delta:        StfPtr(t, ""),
+				zone:         StfPtr(t, ""),
+				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, "java"),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t),
+			},
+			options{Dialect: "java"}},
+		{"locale flag",
+			&RootCommand{
+				ver:          &falsePtr,
+				local:        &falsePtr,
+				utc:          &falsePtr,
+				all:          &falsePtr,
+				copy:         &falsePtr,
+				paste:        &falsePtr,
+				milliseconds: &falsePtr,
+				format:       StfPtr(t, ""),
+				delta:        StfPtr(t, ""),
+				zone:         StfPtr(t, ""),
+				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, "fr_FR"),
+				calendar:     SlicePtr(t),
+			},
+			options{Locale: "fr_FR"}},
+		{"calendar flag",
+			&RootCommand{
+				ver:          &falsePtr,
+				local:        &falsePtr,
+				utc:          &falsePtr,
+				all:          &falsePtr,
+				copy:         &falsePtr,
+				paste:        &falsePtr,
+				milliseconds: &falsePtr,
+				format:       StfPtr(t, ""),
+				delta:        StfPtr(t, ""),
+				zone:         StfPtr(t, ""),
+				tf:           &falsePtr,
+				minYear:      IntPtr(t, 0),
+				maxYear:      IntPtr(t, 0),
+				id:           StfPtr(t, ""),
+				snowflake:    StfPtr(t, ""),
+				epochBase:    StfPtr(t, ""),
+				outputBase:   StfPtr(t, ""),
+				scale:        StfPtr(t, ""),
+				outputScale:  StfPtr(t, ""),
+				leapSeconds:  StfPtr(t, ""),
+				radix:        IntPtr(t, 0),
+				bytes:        &falsePtr,
+				endian:       StfPtr(t, ""),
+				truncate:     StfPtr(t, ""),
+				round:        StfPtr(t, ""),
+				startOf:      StfPtr(t, ""),
+				endOf:        StfPtr(t, ""),
+				weekend:      StfPtr(t, ""),
+				holidays:     SlicePtr(t),
+				output:       StfPtr(t, ""),
+				epochs:       &falsePtr,
+				verbose:      &falsePtr,
+				debug:        &falsePtr,
+				strict:       &falsePtr,
+				dialect:      StfPtr(t, ""),
+				locale:       StfPtr(t, ""),
+				calendar:     SlicePtr(t, "hebrew", "iso"),
+			},
+			options{Calendar: []string{"hebrew", "iso"}}},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -323,12 +1222,353 @@ func TestRunInit(t *testing.T) {
 	}
 }
 
+func TestRunWarnings(t *testing.T) {
+	saveStdOut := stdOut
+	saveStdErr := stdErr
+	defer func() {
+		stdOut = saveStdOut
+		stdErr = saveStdErr
+	}()
+
+	tests := []struct {
+		name    string
+		args    []string
+		options options
+		want    string
+		err     error
+	}{
+		{"plausible", []string{"1601167426"}, options{}, "", nil},
+		{"looks like milliseconds", []string{"1601167426000"}, options{}, "warning: value looks like milliseconds, did you mean -m?\n", nil},
+		{"looks like seconds", []string{"1601167426"}, options{Milliseconds: true}, "warning: value looks like seconds, did you mean to omit -m?\n", nil},
+		{"max year", []string{"1601167426000"}, options{MaxYear: 3000}, "", dat.ErrOutOfRange},
+		{"on leap second", []string{"1483228836"}, options{Scale: "tai"}, "note: input is on leap second 2016-12-31T23:59:60Z\n", nil},
+		{"near leap second", []string{"1483228810"}, options{}, "note: input is 10s after leap second 2016-12-31T23:59:60Z\n", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdOut = new(bytes.Buffer)
+			errBuffer := new(bytes.Buffer)
+			stdErr = errBuffer
+
+			err := RunE(test.options, test.args)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, errBuffer.String())
+		})
+	}
+}
+
+func TestRunFallbacks(t *testing.T) {
+	saveStdOut := stdOut
+	saveStdErr := stdErr
+	defer func() {
+		stdOut = saveStdOut
+		stdErr = saveStdErr
+	}()
+
+	tests := []struct {
+		name    string
+		options options
+		want    string
+		stderr  string
+		err     error
+	}{
+		{"valid", options{Format: "2006", Delta: "+2bd", Zone: "UTC"}, "2023\n", "", nil},
+		{"java format", options{Format: "yyyy", UTC: true}, "11/14/2023 22:13:20 +0000\n",
+			"warning: \"yyyy\" is not a valid layout, using the default format\n" +
+				"hint: looks like a java pattern, the layout is \"2006\"\n" +
+				"hint: layouts write the reference time \"01/02/2006 15:04:05 -0700\", or name a format such as RFC3339\n" +
+				"hint: or use --format-dialect java\n", nil},
+		{"bad delta and zone", options{Delta: "3x", Zone: "Nowhere"}, "1700000000\n",
+			"warning: invalid delta: time: unknown unit \"x\" in duration \"3x\", ignoring it\n" +
+				"warning: invalid zone: unknown time zone Nowhere, ignoring it\n", nil},
+		{"strict format", options{Format: "hello", Strict: true}, "", "", dat.ErrInvalidLayout},
+		{"strict delta", options{Delta: "3x", Strict: true}, "", "", nil},
+		{"dialect", options{Format: "%Y-%m-%d", Dialect: "strftime", UTC: true}, "2023-11-14\n", "", nil},
+		{"java format in go", options{Format: "yyyy", Strict: true}, "", "", dat.ErrInvalidLayout},
+		{"untranslatable format", options{Format: "%Y %U", Dialect: "strftime", Strict: true}, "", "", dat.ErrInvalidLayout},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outputBuffer := new(bytes.Buffer)
+			stdOut = outputBuffer
+			errBuffer := new(bytes.Buffer)
+			stdErr = errBuffer
+
+			err := RunE(test.options, []string{"1700000000"})
+			switch {
+			case test.err != nil:
+				assert.ErrorIs(t, err, test.err)
+			case test.options.Strict:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.want, outputBuffer.String())
+			assert.Equal(t, test.stderr, errBuffer.String())
+		})
+	}
+}
+
+func TestRunTrace(t *testing.T) {
+	saveStdOut := stdOut
+	saveStdErr := stdErr
+	defer func() {
+		stdOut = saveStdOut
+		stdErr = saveStdErr
+	}()
+
+	tests := []struct {
+		name     string
+		options  options
+		contains []string
+		excludes []string
+	}{
+		{"quiet", options{Tf: true}, nil, []string{"level="}},
+		{"verbose", options{Tf: true, Verbose: true, Delta: "1h"},
+			[]string{`level=INFO msg="parsing input as a time format" input=2023-11-14T22:13:20Z`, `msg="delta applied" delta=1h0m0s`},
+			[]string{"level=DEBUG", "time="}},
+		{"debug", options{Tf: true, Debug: true},
+			[]string{`level=DEBUG msg="layout did not match" format=ANSIC`, `msg="input matched a time format"`}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outputBuffer := new(bytes.Buffer)
+			stdOut = outputBuffer
+			errBuffer := new(bytes.Buffer)
+			stdErr = errBuffer
+
+			assert.NoError(t, RunE(test.options, []string{"2023-11-14T22:13:20Z"}))
+			assert.NotContains(t, outputBuffer.String(), "level=")
+			for _, s := range test.contains {
+				assert.Contains(t, errBuffer.String(), s)
+			}
+			for _, s := range test.excludes {
+				assert.NotContains(t, errBuffer.String(), s)
+			}
+		})
+	}
+}
+
+func TestEnvLocale(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"unset", nil, ""},
+		{"lang", map[string]string{"LANG": "de_DE.UTF-8"}, "de_DE.UTF-8"},
+		{"lc_time over lang", map[string]string{"LC_TIME": "fr_FR", "LANG": "de_DE"}, "fr_FR"},
+		{"lc_all over lc_time", map[string]string{"LC_ALL": "ja_JP", "LC_TIME": "fr_FR"}, "ja_JP"},
+		{"posix", map[string]string{"LANG": "C.UTF-8"}, ""},
+		{"unknown", map[string]string{"LANG": "sr_RS"}, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, key := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+				t.Setenv(key, test.env[key])
+			}
+			assert.Equal(t, test.want, envLocale())
+		})
+	}
+}
+
+func TestRunInput(t *testing.T) {
+	saveStdOut := stdOut
+	saveStdErr := stdErr
+	saveTimeNow := timeNow
+	defer func() {
+		stdOut = saveStdOut
+		stdErr = saveStdErr
+		timeNow = saveTimeNow
+	}()
+	timeNow = func() time.Time {
+		return time.Unix(1601167426, 123)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		options options
+		want    string
+	}{
+		{"now", nil, options{}, "1601167426\n"},
+		{"now in another epoch base", nil, options{EpochBase: "ntp"}, "1601167426\n"},
+		{"now in another scale", nil, options{Scale: "tai"}, "1601167426\n"},
+		{"now in hex", nil, options{Radix: 16, Bytes: true, Endian: "big"}, "0x5f6fe042\n"},
+		{"hex prefix", []string{"0x5f6fe042"}, options{}, "1601167426\n"},
+		{"radix", []string{"5f6fe042"}, options{Radix: 16}, "0x5f6fe042\n"},
+		{"little endian bytes", []string{"42e06f5f"}, options{Bytes: true, Endian: "little"}, "1601167426\n"},
+		{"big endian bytes", []string{"5f6fe042"}, options{Bytes: true, Endian: "big"}, "1601167426\n"},
+		{"truncate", []string{"1601167426"}, options{Truncate: "hour"}, "1601164800\n"},
+		{"round", []string{"1601167426"}, options{Round: "h"}, "1601168400\n"},
+		{"start of day in a zone", nil, options{StartOf: "day", Zone: tzLosAngeles, UTC: true}, "  utc: 09/26/2020 07:00:00 +0000\n zone: 09/26/2020 00:00:00 -0700\n"},
+		{"end of month", []string{"1601167426"}, options{EndOf: "month", Zone: "UTC"}, "09/30/2020 23:59:59 +0000\n"},
+		{"business days", []string{"1601167426"}, options{Delta: "+2bd", Zone: "UTC"}, "09/29/2020 00:43:46 +0000\n"},
+		{"business days with a weekend", []string{"1601167426"}, options{Delta: "+2bd", Weekend: "sun-mon", Zone: "UTC"}, "09/30/2020 00:43:46 +0000\n"},
+		{"locale", []string{"1601167426"}, options{Locale: "fr_FR.UTF-8", Format: "Monday 2 January 2006", Zone: "UTC"}, "dimanche 27 septembre 2020\n"},
+		{"time format in a locale", []string{"27 septembre 2020 00:43"}, options{Tf: true, Locale: "fr", Zone: "UTC"}, "27/09/2020 00:43:00 +0000\n"},
+		{"fixed offset zone", []string{"1601167426"}, options{Zone: "UTC-8", UTC: true},
+			"  utc: 09/27/2020 00:43:46 +0000\n zone: 09/26/2020 16:43:46 -0800\n" + sameZones(-8*3600)},
+		{"fixed offset zone alone", []string{"1601167426"}, options{Zone: "+05:30"}, "09/27/2020 06:13:46 +0530\n" + sameZones(19800)},
+		{"fixed offset zone formatted", []string{"1601167426"}, options{Zone: "+05:30", Format: "2006"}, "2020\n"},
+		{"posix zone", []string{"1601167426"}, options{Zone: "EST5EDT,M3.2.0,M11.1.0"}, "09/26/2020 20:43:46 -0400\n"},
+		{"calendars", []string{"1601167426"}, options{Calendar: []string{"hebrew", "japan"}, Zone: tzLosAngeles},
+			"09/26/2020 17:43:46 -0700\ncalendars:\n  hebrew: 8 Tishrei 5781\n  japanese: Reiwa 2-09-26 (令和2年9月26日)\n"},
+		{"calendar out of range", []string{"-5364662400"}, options{Calendar: []string{"japanese"}, Zone: "UTC"},
+			"01/01/1800 00:00:00 +0000\ncalendars:\n  japanese: error: japanese dates start in 1873, when japan adopted the gregorian calendar\n"},
+		{"time format in a dialect", []string{"27.09.2020 00:43"}, options{Tf: true, Format: "%d.%m.%Y %H:%M", Dialect: "python", Zone: "UTC"}, "27.09.2020 00:43\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outputBuffer := new(bytes.Buffer)
+			stdOut = outputBuffer
+			stdErr = new(bytes.Buffer)
+
+			assert.NoError(t, RunE(test.options, test.args))
+			assert.Equal(t, test.want, outputBuffer.String())
+		})
+	}
+
+	for _, opts := range []options{{Radix: 1}, {Bytes: true, Endian: "middle"}, {Truncate: "day", EndOf: "day"}, {Round: "fortnight"},
+		{Weekend: "someday"}, {Holidays: []string{"missing.ics"}}, {Dialect: "klingon"}, {Locale: "klingon"},
+		{Calendar: []string{"mayan"}}} {
+		assert.Error(t, RunE(opts, []string{"1"}))
+	}
+
+	// the environment is only read with --locale env
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_TIME", "")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	for locale, want := range map[string]string{"": "09/27/2020 00:43:46 +0000\n", "env": "27.09.2020 00:43:46 +0000\n"} {
+		outputBuffer := new(bytes.Buffer)
+		stdOut = outputBuffer
+		assert.NoError(t, RunE(options{Locale: locale, Zone: "UTC"}, []string{"1601167426"}))
+		assert.Equal(t, want, outputBuffer.String(), locale)
+	}
+}
+
+func TestRunMultiple(t *testing.T) {
+	saveStdOut := stdOut
+	saveStdErr := stdErr
+	defer func() {
+		stdOut = saveStdOut
+		stdErr = saveStdErr
+	}()
+
+	tests := []struct {
+		name     string
+		args     []string
+		options  options
+		want     string
+		wantErr  string
+		exitCode int
+	}{
+		{"epochs", []string{"1700000000", "1700003600"}, options{UTC: true},
+			"1700000000: 11/14/2023 22:13:20 +0000\n1700003600: 11/14/2023 23:13:20 +0000\n", "", ExitOK},
+		{"grouped", []string{"1700000000", "1700003600"}, options{Local: true, UTC: true, Zone: "UTC"},
+			"1700000000:\n  local: " + time.Unix(1700000000, 0).Local().Format(dat.DateFormat) + "\n    utc: 11/14/2023 22:13:20 +0000\n   zone: 11/14/2023 22:13:20 +0000\n" +
+				"1700003600:\n  local: " + time.Unix(1700003600, 0).Local().Format(dat.DateFormat) + "\n    utc: 11/14/2023 23:13:20 +0000\n   zone: 11/14/2023 23:13:20 +0000\n", "", ExitOK},
+		{"failure", []string{"1700000000", "asdf", "1700003600"}, options{UTC: true},
+			"1700000000: 11/14/2023 22:13:20 +0000\n1700003600: 11/14/2023 23:13:20 +0000\n", "error: asdf: ", ExitNotAnEpoch},
+		{"warnings", []string{"1700000000", "1700000000000"}, options{},
+			"1700000000: 1700000000\n1700000000000: 1700000000000\n", "warning: 1700000000000: ", ExitOK},
+		{"snowflake", []string{"1700000000", "175928847299117063"}, options{UTC: true, Snowflake: "discord"},
+			"1700000000: 11/14/2023 22:13:20 +0000\n175928847299117063: 04/30/2016 11:18:25 +0000\n",
+			"note: 175928847299117063 is too long for an epoch, read as a snowflake id", ExitOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outputBuffer := new(bytes.Buffer)
+			errBuffer := new(bytes.Buffer)
+			stdOut = outputBuffer
+			stdErr = errBuffer
+
+			err := RunE(test.options, test.args)
+			assert.Equal(t, test.exitCode, ExitCode(err))
+			assert.Equal(t, test.want, outputBuffer.String())
+			assert.Contains(t, errBuffer.String(), test.wantErr)
+		})
+	}
+
+	t.Run("summary", func(t *testing.T) {
+		outputBuffer := new(bytes.Buffer)
+		stdOut = outputBuffer
+		stdErr = new(bytes.Buffer)
+
+		err := RunE(options{All: true}, []string{"1700003600", "nope", "1700000000"})
+		assert.EqualError(t, err, "1 of 3 inputs could not be converted")
+		assert.Contains(t, outputBuffer.String(), "1700003600:\n  epoch: 1700003600\n")
+		assert.True(t, strings.HasSuffix(outputBuffer.String(),
+			"summary:\n  inputs: 3\n  failed: 1\n  earliest: 1700000000\n  latest: 1700003600\n  span: 1h0m0s\n"))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		outputBuffer := new(bytes.Buffer)
+		stdOut = outputBuffer
+		stdErr = new(bytes.Buffer)
+
+		err := RunE(options{Output: "json"}, []string{"1700000000", "nope"})
+		assert.Equal(t, ExitNotAnEpoch, ExitCode(err))
+		var items []jsonItem
+		require.NoError(t, json.Unmarshal(outputBuffer.Bytes(), &items))
+		require.Len(t, items, 2)
+		assert.Equal(t, "1700000000", items[0].Value)
+		assert.Equal(t, int64(1700000000), items[0].Result.Epoch)
+		assert.Empty(t, items[0].Error)
+		assert.Nil(t, items[1].Result)
+		assert.NotEmpty(t, items[1].Error)
+
+		// the first input failing still lists every input
+		outputBuffer.Reset()
+		err = RunE(options{Output: "json"}, []string{"zz", "1700000000"})
+		assert.Equal(t, ExitNotAnEpoch, ExitCode(err))
+		items = nil
+		require.NoError(t, json.Unmarshal(outputBuffer.Bytes(), &items))
+		require.Len(t, items, 2)
+		assert.Nil(t, items[0].Result)
+		assert.NotEmpty(t, items[0].Error)
+		assert.Equal(t, int64(1700000000), items[1].Result.Epoch)
+
+		outputBuffer.Reset()
+		assert.NoError(t, RunE(options{Output: "json", Tf: true}, []string{"2023-11-14T22:13:20Z"}))
+		var res dat.Result
+		require.NoError(t, json.Unmarshal(outputBuffer.Bytes(), &res))
+		assert.Equal(t, int64(1700000000), res.Epoch)
+		assert.Equal(t, "RFC3339", res.DetectedFormat)
+
+		outputBuffer.Reset()
+		assert.NoError(t, RunE(options{Output: "json", Epochs: true}, []string{"1700000000"}))
+		res = dat.Result{}
+		require.NoError(t, json.Unmarshal(outputBuffer.Bytes(), &res))
+		assert.Equal(t, dat.Epochs(time.Unix(1700000000, 0)), res.Epochs)
+
+		outputBuffer.Reset()
+		assert.NoError(t, RunE(options{Output: "json", Epochs: true, OutputBase: "ntp"}, []string{"1700000000"}))
+		res = dat.Result{}
+		require.NoError(t, json.Unmarshal(outputBuffer.Bytes(), &res))
+		assert.Equal(t, []dat.BaseValue{{Base: dat.BaseNTP, Value: "3908988800"}}, res.EpochBases)
+
+		outputBuffer.Reset()
+		assert.NoError(t, RunE(options{Output: "json", Zone: "+05:30"}, []string{"1700000000"}))
+		res = dat.Result{}
+		require.NoError(t, json.Unmarshal(outputBuffer.Bytes(), &res))
+		assert.Equal(t, dat.ZonesAtOffset(19800, time.Now()), res.OffsetZones)
+	})
+
+	assert.EqualError(t, RunE(options{Output: "yaml"}, nil), `unknown output "yaml", expected text or json`)
+}
+
 func TestRootCommand_BuildOutput(t *testing.T) {
 	tm := time.Now()
 	tmStr := strconv.FormatInt(tm.Unix(), 10)
 	tmStrPlus100h := strconv.FormatInt(tm.Add(100*time.Hour).Unix(), 10)
 	tmStrMinus100h := strconv.FormatInt(tm.Add(-100*time.Hour).Unix(), 10)
 	tmStrMillis := strconv.FormatInt(tm.UnixNano()/int64(time.Millisecond), 10)
+	leap := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
 	laZone, err := time.LoadLocation(tzLosAngeles)
 	if err != nil {
 		t.Fatal(err)
@@ -350,31 +1590,70 @@ func TestRootCommand_BuildOutput(t *testing.T) {
 		{"format", tm, options{Format: "rfc3339"},
 			fmt.Sprintln(tm.Format(time.RFC3339))},
 		{"zone", tm, options{Zone: tzLosAngeles},
-			fmt.Sprintln(tm.In(laZone).Format(DateFormat))},
+			fmt.Sprintln(tm.In(laZone).Format(dat.DateFormat))},
+		{"output base", tm, options{OutputBase: "ntp"},
+			fmt.Sprintln(dat.ToEpochBase(tm, dat.BaseNTP))},
+		{"radix", tm, options{Radix: 16},
+			fmt.Sprintln(dat.FormatInteger(tm.Unix(), 16))},
+		{"output scale", tm, options{OutputScale: "tai"},
+			fmt.Sprintln(tm.Unix() + 37)},
+		{"epochs", tm, options{Epochs: true, Milliseconds: true},
+			fmt.Sprintln(tmStrMillis) + FormatEpochs(options{Epochs: true}.converter().At(tm))},
 		{"utc", tm, options{UTC: true},
-			fmt.Sprintln(tm.UTC().Format(DateFormat))},
+			fmt.Sprintln(tm.UTC().Format(dat.DateFormat))},
 		{"utc and zone", tm, options{UTC: true, Zone: tzLosAngeles},
-			fmt.Sprintf("  utc: %s\n zone: %s\n", tm.UTC().Format(DateFormat), tm.In(laZone).Format(DateFormat))},
+			fmt.Sprintf("  utc: %s\n zone: %s\n", tm.UTC().Format(dat.DateFormat), tm.In(laZone).Format(dat.DateFormat))},
 		{"local", tm, options{Local: true},
-			fmt.Sprintln(tm.Local().Format(DateFormat))},
+			fmt.Sprintln(tm.Local().Format(dat.DateFormat))},
 		{"local and zone", tm, options{Local: true, Zone: tzLosAngeles},
-			fmt.Sprintf("local: %s\n zone: %s\n", tm.Local().Format(DateFormat), tm.In(laZone).Format(DateFormat))},
+			fmt.Sprintf("local: %s\n zone: %s\n", tm.Local().Format(dat.DateFormat), tm.In(laZone).Format(dat.DateFormat))},
 		{"utc and local", tm, options{Local: true, UTC: true},
-			fmt.Sprintf("local: %s\n  utc: %s\n", tm.Local().Format(DateFormat), tm.UTC().Format(DateFormat))},
+			fmt.Sprintf("local: %s\n  utc: %s\n", tm.Local().Format(dat.DateFormat), tm.UTC().Format(dat.DateFormat))},
+		{"all with id", tm, options{All: true, detectedID: &dat.ID{Kind: dat.IDObjectID, Components: []dat.IDComponent{{Name: "counter", Value: "1"}}}},
+			fmt.Sprintf("id: objectid\n  counter: 1\nepoch: %d\nlocal: %s\n  utc: %s\n", tm.Unix(), tm.Local().Format(dat.DateFormat), tm.UTC().Format(dat.DateFormat)) +
+				metadataText(tm, tm, nil) +
+				FormatBases(dat.AllBases(tm))},
 		{"all with detection", tm, options{All: true, detectedFormat: "rfc3339"},
-			fmt.Sprintf("detected: %s\nepoch: %d\nlocal: %s\n  utc: %s\n", "rfc3339", tm.Unix(), tm.Local().Format(DateFormat), tm.UTC().Format(DateFormat))},
+			fmt.Sprintf("detected: %s\nepoch: %d\nlocal: %s\n  utc: %s\n", "rfc3339", tm.Unix(), tm.Local().Format(dat.DateFormat), tm.UTC().Format(dat.DateFormat)) +
+				metadataText(tm, tm, nil) +
+				FormatBases(dat.AllBases(tm))},
 		{"all", tm, options{All: true},
-			fmt.Sprintf("epoch: %d\nlocal: %s\n  utc: %s\n", tm.Unix(), tm.Local().Format(DateFormat), tm.UTC().Format(DateFormat))},
+			fmt.Sprintf("epoch: %d\nlocal: %s\n  utc: %s\n", tm.Unix(), tm.Local().Format(dat.DateFormat), tm.UTC().Format(dat.DateFormat)) +
+				metadataText(tm, tm, nil) +
+				FormatBases(dat.AllBases(tm))},
+		{"all on leap second", leap.Add(-time.Second), options{All: true, OutputScale: "tai", leapSecond: &dat.LeapSecondNote{Leap: leap, Offset: -time.Second, On: true}},
+			fmt.Sprintf("epoch: %d\nscale: tai\n leap: on leap second 2016-12-31T23:59:60Z\nlocal: %s\n  utc: %s\n", leap.Unix()+35, leap.Add(35*time.Second).Local().Format(dat.DateFormat), leap.Add(35*time.Second).UTC().Format(dat.DateFormat)) +
+				metadataText(leap.Add(-time.Second), leap.Add(35*time.Second), nil) +
+				FormatBases(dat.AllBases(leap.Add(-time.Second)))},
+		{"all near leap second", leap.Add(time.Second), options{All: true},
+			fmt.Sprintf("epoch: %d\n leap: 1s after leap second 2016-12-31T23:59:60Z\nlocal: %s\n  utc: %s\n", leap.Unix()+1, leap.Add(time.Second).Local().Format(dat.DateFormat), leap.Add(time.Second).UTC().Format(dat.DateFormat)) +
+				metadataText(leap.Add(time.Second), leap.Add(time.Second), nil) +
+				FormatBases(dat.AllBases(leap.Add(time.Second)))},
+		{"all with calendars", tm, options{All: true, Calendar: []string{"all"}},
+			fmt.Sprintf("epoch: %d\nlocal: %s\n  utc: %s\n", tm.Unix(), tm.Local().Format(dat.DateFormat), tm.UTC().Format(dat.DateFormat)) +
+				FormatCalendars(dat.NewConverter(dat.WithCalendars(dat.Calendars...)).At(tm).Calendars) +
+				metadataText(tm, tm, nil) +
+				FormatBases(dat.AllBases(tm))},
 		{"all with zone", tm, options{All: true, Zone: tzLosAngeles},
-			fmt.Sprintf("epoch: %d\nlocal: %s\n  utc: %s\n zone: %s\n", tm.Unix(), tm.Local().Format(DateFormat), tm.UTC().Format(DateFormat), tm.In(laZone).Format(DateFormat))},
+			fmt.Sprintf("epoch: %d\nlocal: %s\n  utc: %s\n zone: %s\n", tm.Unix(), tm.Local().Format(dat.DateFormat), tm.UTC().Format(dat.DateFormat), tm.In(laZone).Format(dat.DateFormat)) +
+				metadataText(tm, tm, laZone) +
+				FormatBases(dat.AllBases(tm))},
 		{"ms all", tm, options{Milliseconds: true, All: true},
-			fmt.Sprintf("epoch: %d\nlocal: %s\n  utc: %s\n", tm.UnixNano()/int64(time.Millisecond), tm.Local().Format(DateFormat), tm.UTC().Format(DateFormat))},
+			fmt.Sprintf("epoch: %d\nlocal: %s\n  utc: %s\n", tm.UnixNano()/int64(time.Millisecond), tm.Local().Format(dat.DateFormat), tm.UTC().Format(dat.DateFormat)) +
+				metadataText(tm, tm, nil) +
+				FormatBases(dat.AllBases(tm))},
 		{"ms all with zone", tm, options{Milliseconds: true, All: true, Zone: tzLosAngeles},
-			fmt.Sprintf("epoch: %d\nlocal: %s\n  utc: %s\n zone: %s\n", tm.UnixNano()/int64(time.Millisecond), tm.Local().Format(DateFormat), tm.UTC().Format(DateFormat), tm.In(laZone).Format(DateFormat))},
+			fmt.Sprintf("epoch: %d\nlocal: %s\n  utc: %s\n zone: %s\n", tm.UnixNano()/int64(time.Millisecond), tm.Local().Format(dat.DateFormat), tm.UTC().Format(dat.DateFormat), tm.In(laZone).Format(dat.DateFormat)) +
+				metadataText(tm, tm, laZone) +
+				FormatBases(dat.AllBases(tm))},
 		{"ms all with format", tm, options{Milliseconds: true, All: true, Format: "rfc3339"},
-			fmt.Sprintf("epoch: %d\nlocal: %s\n  utc: %s\n", tm.UnixNano()/int64(time.Millisecond), tm.Local().Format(time.RFC3339), tm.UTC().Format(time.RFC3339))},
+			fmt.Sprintf("epoch: %d\nlocal: %s\n  utc: %s\n", tm.UnixNano()/int64(time.Millisecond), tm.Local().Format(time.RFC3339), tm.UTC().Format(time.RFC3339)) +
+				metadataText(tm, tm, nil) +
+				FormatBases(dat.AllBases(tm))},
 		{"ms all with format and zone", tm, options{Milliseconds: true, All: true, Zone: tzLosAngeles, Format: "rfc3339"},
-			fmt.Sprintf("epoch: %d\nlocal: %s\n  utc: %s\n zone: %s\n", tm.UnixNano()/int64(time.Millisecond), tm.Local().Format(time.RFC3339), tm.UTC().Format(time.RFC3339), tm.In(laZone).Format(time.RFC3339))},
+			fmt.Sprintf("epoch: %d\nlocal: %s\n  utc: %s\n zone: %s\n", tm.UnixNano()/int64(time.Millisecond), tm.Local().Format(time.RFC3339), tm.UTC().Format(time.RFC3339), tm.In(laZone).Format(time.RFC3339)) +
+				metadataText(tm, tm, laZone) +
+				FormatBases(dat.AllBases(tm))},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -384,56 +1663,23 @@ func TestRootCommand_BuildOutput(t *testing.T) {
 	}
 }
 
-func TestParseEpochTime(t *testing.T) {
-	timeEpoch := int64(1572762509)
-	timeEpochMillis := int64(1572762509000)
-	tmStr := strconv.FormatInt(timeEpoch, 10)
-	tmStrMillis := strconv.FormatInt(timeEpochMillis, 10)
-	tests := []struct {
-		name           string
-		str            string
-		isMilliseconds bool
-		want           time.Time
-		error          bool
-	}{
-		{"can't parse", "qqqqqq", false, time.Time{}, true},
-		{"parsed", tmStr, false, time.Unix(timeEpoch, 0), false},
-		{"parsed", tmStrMillis, true, time.Unix(0, timeEpochMillis*int64(time.Millisecond)), false},
-	}
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			got, err := ParseEpochTime(test.str, test.isMilliseconds)
-			if test.error {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-				assert.True(t, test.want.Equal(got), "want:%d got:%d", test.want.UnixNano(), got.UnixNano())
-			}
-		})
+// sameZones is the line of the zones currently at a fixed offset
+func sameZones(offset int) string {
+	zones := dat.ZonesAtOffset(offset, time.Now())
+	if len(zones) == 0 {
+		return ""
 	}
+	return " same: " + strings.Join(zones, ", ") + "\n"
 }
 
-func TestTruncateString(t *testing.T) {
-	tests := []struct {
-		name string
-		size int
-		want string
-	}{
-		{"", 0, ""},
-		{"", -1, ""},
-		{"no trunc", 10, "no trunc"},
-		{"ab", 1, "a"},
-		{"abc", 2, "ab"},
-		{"abc", 3, "abc"},
-		{"abcd", 3, "..."},
-		{"happy trees", 8, "happy..."},
-	}
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			got := TruncateString(test.name, test.size)
-			assert.Equal(t, test.want, got)
-		})
+// metadataText is the metadata section of tm displayed as reading
+func metadataText(tm, reading time.Time, zone *time.Location) string {
+	text := fmt.Sprintf("metadata:\n  unix: %d\n  unix ms: %d\n", tm.Unix(), tm.UnixMilli())
+	text += fmt.Sprintf("  local: %s\n  utc: %s\n", dat.Describe(reading.Local()), dat.Describe(reading.UTC()))
+	if zone != nil {
+		text += fmt.Sprintf("  zone: %s\n", dat.Describe(reading.In(zone)))
 	}
+	return text
 }
 
 func StfPtr(t *testing.T, s string) *string {
@@ -441,56 +1687,51 @@ func StfPtr(t *testing.T, s string) *string {
 	return &s
 }
 
-func TestFormatOutput(t *testing.T) {
-	testTime := time.Now()
+func SlicePtr(t *testing.T, s ...string) *[]string {
+	t.Helper()
+	return &s
+}
 
-	tests := []struct {
-		name   string
-		time   time.Time
-		format string
-		want   string
-	}{
-		{"ANSIC", testTime, "ansic", testTime.Format(time.ANSIC)},
-		{"UnixDate", testTime, "UnixDate", testTime.Format(time.UnixDate)},
-		{"RubyDate", testTime, "RubyDate", testTime.Format(time.RubyDate)},
-		{"RFC822", testTime, "RFC822", testTime.Format(time.RFC822)},
-		{"RFC822Z", testTime, "RFC822Z", testTime.Format(time.RFC822Z)},
-		{"RFC850", testTime, "RFC850", testTime.Format(time.RFC850)},
-		{"RFC1123", testTime, "RFC1123", testTime.Format(time.RFC1123)},
-		{"RFC1123Z", testTime, "RFC1123Z", testTime.Format(time.RFC1123Z)},
-		{"RFC3339", testTime, "RFC3339", testTime.Format(time.RFC3339)},
-		{"RFC3339Nano", testTime, "RFC3339Nano", testTime.Format(time.RFC3339Nano)},
-		{"Kitchen", testTime, "Kitchen", testTime.Format(time.Kitchen)},
-		{"Stamp", testTime, "Stamp", testTime.Format(time.Stamp)},
-		{"StampMilli", testTime, "StampMilli", testTime.Format(time.StampMilli)},
-		{"StampMicro", testTime, "StampMicro", testTime.Format(time.StampMicro)},
-		{"StampNano", testTime, "StampNano", testTime.Format(time.StampNano)},
-		{"other format", testTime, "Jan 15:05:04 MST -700 2006", testTime.Format("Jan 15:05:04 MST -700 2006")},
-		{"unknown format", testTime, "NoTaGoOdFoRmAt", testTime.Format(DateFormat)},
-	}
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			got := FormatOutput(test.time, test.format)
-			assert.Equal(t, got, test.want)
-		})
-	}
+func IntPtr(t *testing.T, i int) *int {
+	t.Helper()
+	return &i
 }
 
-func TestAddDelta(t *testing.T) {
-	tests := []struct {
-		name         string
-		time         time.Time
-		delta        string
-		expectedTime time.Time
-	}{
-		{"no delta, no addition", time.Unix(1625211007, 0), "", time.Unix(1625211007, 0)},
-		{"adding 300h2m", time.Unix(1625211007, 0), "300h2m", time.Unix(1626291127, 0)},
-		{"subtracting 300h2m", time.Unix(1625211007, 0), "-300h2m", time.Unix(1624130887, 0)},
-	}
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			got := AddDelta(test.time, test.delta)
-			assert.Equal(t, test.expectedTime, got)
-		})
-	}
+func TestFormatEpochs(t *testing.T) {
+	tm := time.Unix(1700000000, 0)
+	opts := options{Epochs: true}
+	assert.Equal(t, "epochs:\n"+
+		"  s: 1700000000 (0x6553f100)\n"+
+		"  ms: 1700000000000 (0x18bcfe56800)\n"+
+		"  us: 1700000000000000 (0x60a24181e4000)\n"+
+		"  ns: 1700000000000000000 (0x17979cfe362a0000)\n",
+		FormatEpochs(opts.converter().At(tm)))
+
+	opts = options{Epochs: true, Radix: 36, EpochBase: "ntp", OutputBase: "filetime", OutputScale: "tai"}
+	got := FormatEpochs(opts.converter().At(tm))
+	assert.Contains(t, got, "  s: 1700000037 (0x6553f125)\n")
+	assert.Contains(t, got, "  base 36: "+dat.FormatInteger(1700000037, 36)+"\n")
+	assert.Contains(t, got, "  ntp: "+dat.ToEpochBase(tm, dat.BaseNTP)+"\n  filetime: "+dat.ToEpochBase(tm, dat.BaseFILETIME)+"\n")
+
+	opts = options{Epochs: true, EpochBase: "excel", OutputBase: "excel"}
+	assert.Equal(t, 1, strings.Count(FormatEpochs(opts.converter().At(tm)), "excel:"))
+}
+
+func TestFormatCalendars(t *testing.T) {
+	assert.Equal(t, "calendars:\n  iso: 2023-W46-2\n  chinese: error: out of range\n",
+		FormatCalendars([]dat.CalendarDate{
+			{Calendar: dat.CalendarISO, Year: 2023, Week: 46, Day: 2, Text: "2023-W46-2"},
+			{Calendar: dat.CalendarChinese, Error: "out of range"},
+		}))
+}
+
+func TestFormatMetadata(t *testing.T) {
+	opts := options{Zone: tzLosAngeles, Milliseconds: true, All: true}
+	got := FormatMetadata(opts.converter().At(time.Unix(1700000000, 0)))
+	assert.Contains(t, got, "metadata:\n  unix: 1700000000\n  unix ms: 1700000000000\n")
+	assert.Contains(t, got, "  utc: Tuesday, week 2023-W46-2, day 318, Q4, 30 days in month, not a leap year, 80000s since midnight\n")
+	assert.Contains(t, got, "  zone: Tuesday, week 2023-W46-2, day 318, Q4, 30 days in month, not a leap year, 51200s since midnight\n")
+
+	// without --all there is no metadata
+	assert.Empty(t, FormatMetadata(options{}.converter().At(time.Unix(1700000000, 0))))
 }
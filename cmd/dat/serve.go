@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Setheck/dat/pkg/server"
+)
+
+// ServeCommand serves the conversion engine over http
+type ServeCommand struct {
+	cmd *cobra.Command
+
+	addr    *string
+	timeout *time.Duration
+}
+
+// test points
+var listenAndServe = func(ctx context.Context, srv *server.Server) error {
+	return srv.ListenAndServe(ctx)
+}
+
+// NewServeCommand creates a new instance of a ServeCommand
+func NewServeCommand() *ServeCommand {
+	sc := &ServeCommand{}
+	sc.cmd = &cobra.Command{
+		Use:   "serve",
+		Short: "serve conversions over http",
+		Long: `serve exposes the conversion engine over http with the endpoints
+  GET  /convert?value=...  convert a single value
+  POST /batch              convert a json array of values
+  GET  /now                the current time
+  GET  /zones              the available tz database zone names
+convert, batch and now accept the query parameters zone, format, delta, ms, tf, base, outputBase,
+scale, outputScale, radix, bytes, truncate, round, startOf and endOf.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sc.RunE()
+		},
+	}
+	return sc
+}
+
+// ParseFlags parse and assign flags
+func (s *ServeCommand) ParseFlags() {
+	flgs := s.cmd.Flags()
+	s.addr = flgs.String("addr", ":8080", "address to listen on")
+	s.timeout = flgs.Duration("timeout", server.DefaultTimeout, "maximum duration of a request")
+}
+
+// RunE serves until interrupted
+func (s *ServeCommand) RunE() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintln(stdErr, "listening on", *s.addr)
+	return listenAndServe(ctx, &server.Server{Addr: *s.addr, Timeout: *s.timeout})
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Setheck/dat/pkg/server"
+)
+
+func TestServeCommand_ParseFlags(t *testing.T) {
+	sc := NewServeCommand()
+	sc.ParseFlags()
+
+	fset := sc.cmd.Flags()
+	assert.NotNil(t, fset.Lookup("addr"))
+	assert.NotNil(t, fset.Lookup("timeout"))
+	assert.Equal(t, ":8080", *sc.addr)
+	assert.Equal(t, server.DefaultTimeout, *sc.timeout)
+}
+
+func TestServeCommand_RunE(t *testing.T) {
+	saveListenAndServe := listenAndServe
+	saveStdErr := stdErr
+	defer func() {
+		listenAndServe = saveListenAndServe
+		stdErr = saveStdErr
+	}()
+	stdErr = new(bytes.Buffer)
+
+	var got *server.Server
+	listenAndServe = func(ctx context.Context, srv *server.Server) error {
+		got = srv
+		return assert.AnError
+	}
+
+	sc := NewServeCommand()
+	sc.ParseFlags()
+	assert.NoError(t, sc.cmd.Flags().Parse([]string{"--addr", "127.0.0.1:9999", "--timeout", "3s"}))
+
+	err := sc.RunE()
+	assert.ErrorIs(t, err, assert.AnError)
+	if assert.NotNil(t, got) {
+		assert.Equal(t, "127.0.0.1:9999", got.Addr)
+		assert.Equal(t, 3*time.Second, got.Timeout)
+	}
+}
@@ -1,12 +1,13 @@
 module github.com/Setheck/dat
 
-go 1.19
+go 1.21
 
 require (
 	github.com/atotto/clipboard v0.1.4
 	github.com/spf13/cobra v1.4.0
 	github.com/spf13/pflag v1.0.5
 	github.com/stretchr/testify v1.7.0
+	gopkg.in/yaml.v3 v3.0.0
 )
 
 require (
@@ -14,5 +15,4 @@ require (
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/objx v0.3.0 // indirect
-	gopkg.in/yaml.v3 v3.0.0 // indirect
 )
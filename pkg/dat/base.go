@@ -0,0 +1,175 @@
+package dat
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// EpochBase is a system counting time from a fixed instant
+type EpochBase string
+
+const (
+	// BaseUnix seconds since 1970-01-01
+	BaseUnix EpochBase = "unix"
+	// BaseNTP seconds since 1900-01-01
+	BaseNTP EpochBase = "ntp"
+	// BaseGPS seconds since 1980-01-06 including leap seconds
+	BaseGPS EpochBase = "gps"
+	// BaseFILETIME windows FILETIME, 100ns intervals since 1601-01-01
+	BaseFILETIME EpochBase = "filetime"
+	// BaseTicks .NET ticks, 100ns intervals since 0001-01-01
+	BaseTicks EpochBase = "ticks"
+	// BaseExcel excel serial dates, days since 1899-12-30
+	BaseExcel EpochBase = "excel"
+	// BaseMac Cocoa/Mac absolute time, seconds since 2001-01-01
+	BaseMac EpochBase = "mac"
+	// BaseJulianDay days since noon 4713-11-24 BC
+	BaseJulianDay EpochBase = "jd"
+	// BaseModifiedJulianDay days since 1858-11-17
+	BaseModifiedJulianDay EpochBase = "mjd"
+)
+
+// EpochBases are the supported epoch bases
+var EpochBases = []EpochBase{
+	BaseUnix, BaseNTP, BaseGPS, BaseFILETIME, BaseTicks, BaseExcel, BaseMac, BaseJulianDay, BaseModifiedJulianDay,
+}
+
+// baseAliases are alternate names for epoch bases
+var baseAliases = map[string]EpochBase{
+	"windows": BaseFILETIME,
+	"dotnet":  BaseTicks,
+	"cocoa":   BaseMac,
+	"julian":  BaseJulianDay,
+}
+
+// epochSystem describes an epoch base by its origin and unit
+type epochSystem struct {
+	// origin in unix seconds
+	origin int64
+	// unit in nanoseconds
+	unit int64
+	// integer systems do not accept fractions
+	integer bool
+	// decimals shown for fractional values
+	decimals int
+}
+
+const nanosPerDay = 86400 * int64(time.Second)
+
+var epochSystems = map[EpochBase]epochSystem{
+	BaseUnix:              {origin: 0, unit: int64(time.Second), decimals: 9},
+	BaseNTP:               {origin: -2208988800, unit: int64(time.Second), decimals: 9},
+	BaseGPS:               {origin: 315964800, unit: int64(time.Second), decimals: 9},
+	BaseFILETIME:          {origin: -11644473600, unit: 100, integer: true},
+	BaseTicks:             {origin: -62135596800, unit: 100, integer: true},
+	BaseExcel:             {origin: -2209161600, unit: nanosPerDay, decimals: 10},
+	BaseMac:               {origin: 978307200, unit: int64(time.Second), decimals: 9},
+	BaseJulianDay:         {origin: -210866760000, unit: nanosPerDay, decimals: 10},
+	BaseModifiedJulianDay: {origin: -3506716800, unit: nanosPerDay, decimals: 10},
+}
+
+// ParseEpochBase returns the epoch base with the given name or alias, case insensitive.
+func ParseEpochBase(name string) (EpochBase, error) {
+	lower := strings.ToLower(strings.TrimSpace(name))
+	if lower == "" {
+		return BaseUnix, nil
+	}
+	if base, ok := baseAliases[lower]; ok {
+		return base, nil
+	}
+	if _, ok := epochSystems[EpochBase(lower)]; ok {
+		return EpochBase(lower), nil
+	}
+	return "", fmt.Errorf("unknown epoch base %q", name)
+}
+
+var bigNanosPerSecond = big.NewInt(int64(time.Second))
+
+// FromEpochBase converts a value in the given epoch base to a time.
+// Fractions are accepted except for FILETIME and ticks.
+func FromEpochBase(str string, base EpochBase) (time.Time, error) {
+	sys, ok := epochSystems[base]
+	if !ok {
+		return time.Time{}, fmt.Errorf("unknown epoch base %q", base)
+	}
+
+	trimmed := strings.TrimSpace(str)
+	value, ok := new(big.Rat).SetString(trimmed)
+	if !ok || !isDecimal(trimmed) || (sys.integer && !value.IsInt()) {
+		return time.Time{}, notAnEpoch(str)
+	}
+
+	// nanoseconds since the unix epoch
+	nanos := new(big.Rat).Mul(value, new(big.Rat).SetInt64(sys.unit))
+	nanos.Add(nanos, new(big.Rat).SetInt(new(big.Int).Mul(big.NewInt(sys.origin), bigNanosPerSecond)))
+	n := new(big.Int).Quo(nanos.Num(), nanos.Denom())
+
+	sec, nsec := new(big.Int).DivMod(n, bigNanosPerSecond, new(big.Int))
+	if !sec.IsInt64() || sec.Int64() < MinUnix || sec.Int64() > MaxUnix {
+		return time.Time{}, &ParseError{Input: str, Pos: -1, Err: ErrOutOfRange,
+			Suggestions: []string{"value is beyond the range of supported times"}}
+	}
+	tm := time.Unix(sec.Int64(), nsec.Int64())
+
+	if base == BaseGPS {
+		// gps counts leap seconds
+		tm, _ = FromScale(tm, ScaleGPS)
+	}
+	return tm, nil
+}
+
+// isDecimal reports whether s is a plain decimal number, optionally signed and fractional
+func isDecimal(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	digits := 0
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '.' && !strings.Contains(s[i+1:], "."):
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+// ToEpochBase formats the time as a value in the given epoch base.
+func ToEpochBase(tm time.Time, base EpochBase) string {
+	sys, ok := epochSystems[base]
+	if !ok {
+		return ""
+	}
+	if base == BaseGPS {
+		tm = ToScale(tm, ScaleGPS)
+	}
+
+	nanos := new(big.Int).Sub(big.NewInt(tm.Unix()), big.NewInt(sys.origin))
+	nanos.Mul(nanos, bigNanosPerSecond)
+	nanos.Add(nanos, big.NewInt(int64(tm.Nanosecond())))
+	value := new(big.Rat).SetFrac(nanos, big.NewInt(sys.unit))
+
+	if sys.integer || value.IsInt() {
+		// floor, so instants before the origin count down
+		return new(big.Int).Div(value.Num(), value.Denom()).String()
+	}
+	s := value.FloatString(sys.decimals)
+	return strings.TrimRight(strings.TrimRight(s, "0"), ".")
+}
+
+// BaseValue is a time in an epoch base
+type BaseValue struct {
+	Base  EpochBase `json:"base"`
+	Value string    `json:"value"`
+}
+
+// AllBases returns the time in each of the supported epoch bases.
+func AllBases(tm time.Time) []BaseValue {
+	values := make([]BaseValue, 0, len(EpochBases))
+	for _, base := range EpochBases {
+		values = append(values, BaseValue{Base: base, Value: ToEpochBase(tm, base)})
+	}
+	return values
+}
@@ -0,0 +1,88 @@
+package dat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEpochBases(t *testing.T) {
+	tm := time.Unix(1601167426, 0)
+	tests := []struct {
+		base  EpochBase
+		value string
+	}{
+		{BaseUnix, "1601167426"},
+		{BaseNTP, "3810156226"},
+		{BaseGPS, "1285202644"},
+		{BaseFILETIME, "132456410260000000"},
+		{BaseTicks, "637367642260000000"},
+		{BaseExcel, "44101.0303935185"},
+		{BaseMac, "622860226"},
+		{BaseJulianDay, "2459119.5303935185"},
+		{BaseModifiedJulianDay, "59119.0303935185"},
+	}
+	for _, test := range tests {
+		t.Run(string(test.base), func(t *testing.T) {
+			assert.Equal(t, test.value, ToEpochBase(tm, test.base))
+
+			got, err := FromEpochBase(test.value, test.base)
+			assert.NoError(t, err)
+			assert.WithinDuration(t, tm, got, 10*time.Microsecond)
+		})
+	}
+}
+
+func TestFromEpochBase(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		base  EpochBase
+		want  time.Time
+		err   error
+	}{
+		{"excel noon", "44101.5", BaseExcel, time.Date(2020, 9, 27, 12, 0, 0, 0, time.UTC), nil},
+		{"mjd origin", "0", BaseModifiedJulianDay, time.Date(1858, 11, 17, 0, 0, 0, 0, time.UTC), nil},
+		{"jd unix epoch", "2440587.5", BaseJulianDay, time.Unix(0, 0), nil},
+		{"filetime origin", "0", BaseFILETIME, time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC), nil},
+		{"gps epoch", "0", BaseGPS, time.Date(1980, 1, 6, 0, 0, 0, 0, time.UTC), nil},
+		{"gps after leap", "1167264018", BaseGPS, time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC), nil},
+		{"fractional mac", "1.25", BaseMac, time.Date(2001, 1, 1, 0, 0, 1, 250000000, time.UTC), nil},
+		{"fractional filetime", "1.5", BaseFILETIME, time.Time{}, ErrNotAnEpoch},
+		{"hex", "0x10", BaseNTP, time.Time{}, ErrNotAnEpoch},
+		{"fraction", "1/2", BaseExcel, time.Time{}, ErrNotAnEpoch},
+		{"out of range", "1e400", BaseJulianDay, time.Time{}, ErrNotAnEpoch},
+		{"too large", "99999999999999999999999", BaseJulianDay, time.Time{}, ErrOutOfRange},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := FromEpochBase(test.value, test.base)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, test.want.Equal(got), "want:%s got:%s", test.want, got.UTC())
+		})
+	}
+}
+
+func TestParseEpochBase(t *testing.T) {
+	got, err := ParseEpochBase("Cocoa")
+	assert.NoError(t, err)
+	assert.Equal(t, BaseMac, got)
+
+	got, err = ParseEpochBase("")
+	assert.NoError(t, err)
+	assert.Equal(t, BaseUnix, got)
+
+	_, err = ParseEpochBase("lotus")
+	assert.Error(t, err)
+}
+
+func TestAllBases(t *testing.T) {
+	got := AllBases(time.Unix(0, 0))
+	assert.Len(t, got, len(EpochBases))
+	assert.Equal(t, BaseValue{Base: BaseUnix, Value: "0"}, got[0])
+}
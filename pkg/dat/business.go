@@ -0,0 +1,233 @@
+package dat
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultWeekend is the weekend of a BusinessCalendar unless another is set
+var DefaultWeekend = []time.Weekday{time.Saturday, time.Sunday}
+
+// date is a day on the calendar, independent of location
+type date struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+// dateOf is the date of tm in its location
+func dateOf(tm time.Time) date {
+	y, m, d := tm.Date()
+	return date{y, m, d}
+}
+
+// add returns the date days later
+func (d date) add(days int) date {
+	return dateOf(time.Date(d.year, d.month, d.day+days, 12, 0, 0, 0, time.UTC))
+}
+
+// noon is the middle of the date in UTC
+func (d date) noon() time.Time {
+	return time.Date(d.year, d.month, d.day, 12, 0, 0, 0, time.UTC)
+}
+
+// yearlyHoliday is a holiday on the same month and day every interval years from a year
+type yearlyHoliday struct {
+	month    time.Month
+	day      int
+	name     string
+	from     int
+	interval int
+	// until is the last date it may fall on, the zero date for none
+	until date
+}
+
+// on reports whether the holiday falls on d
+func (h yearlyHoliday) on(d date) bool {
+	if d.month != h.month || d.day != h.day || d.year < h.from {
+		return false
+	}
+	if h.until.year != 0 && before(h.until, d) {
+		return false
+	}
+	return (d.year-h.from)%h.interval == 0
+}
+
+// BusinessCalendar knows which days are business days, every day that is
+// neither on the weekend nor a holiday.
+type BusinessCalendar struct {
+	weekend  [7]bool
+	holidays map[date]string
+	yearly   []yearlyHoliday
+}
+
+// NewBusinessCalendar creates a calendar without holidays with the given weekend days,
+// or the DefaultWeekend when none are given.
+func NewBusinessCalendar(weekend ...time.Weekday) *BusinessCalendar {
+	if len(weekend) == 0 {
+		weekend = DefaultWeekend
+	}
+	cal := &BusinessCalendar{holidays: make(map[date]string)}
+	cal.SetWeekend(weekend)
+	return cal
+}
+
+// SetWeekend replaces the weekend days, an empty weekend makes every day but holidays a business day.
+func (b *BusinessCalendar) SetWeekend(weekend []time.Weekday) {
+	b.weekend = [7]bool{}
+	for _, d := range weekend {
+		b.weekend[d] = true
+	}
+}
+
+// Weekend returns the weekend days from sunday to saturday
+func (b *BusinessCalendar) Weekend() []time.Weekday {
+	var days []time.Weekday
+	for d, on := range b.weekend {
+		if on {
+			days = append(days, time.Weekday(d))
+		}
+	}
+	return days
+}
+
+// ParseWeekend reads a comma separated list of weekdays or ranges of weekdays,
+// such as sat,sun or fri-sat. Ranges may wrap past saturday. none is an empty weekend.
+func ParseWeekend(s string) ([]time.Weekday, error) {
+	str := strings.TrimSpace(s)
+	if strings.EqualFold(str, "none") {
+		return nil, nil
+	}
+	var days []time.Weekday
+	seen := [7]bool{}
+	for _, part := range strings.Split(str, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := ParseWeekday(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weekend %q: %w", s, err)
+		}
+		last := first
+		if isRange {
+			if last, err = ParseWeekday(to); err != nil {
+				return nil, fmt.Errorf("invalid weekend %q: %w", s, err)
+			}
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			if !seen[d] {
+				seen[d] = true
+				days = append(days, d)
+			}
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// AddHoliday marks the date of tm, in its location, as a holiday.
+func (b *BusinessCalendar) AddHoliday(tm time.Time, name string) {
+	b.holidays[dateOf(tm)] = name
+}
+
+// Holiday returns the name of the holiday on the date of tm, in its location.
+func (b *BusinessCalendar) Holiday(tm time.Time) (string, bool) {
+	d := dateOf(tm)
+	if name, ok := b.holidays[d]; ok {
+		return name, true
+	}
+	for _, h := range b.yearly {
+		if h.on(d) {
+			return h.name, true
+		}
+	}
+	return "", false
+}
+
+// IsBusinessDay reports whether the date of tm, in its location, is a business day.
+func (b *BusinessCalendar) IsBusinessDay(tm time.Time) bool {
+	if b.weekend[tm.Weekday()] {
+		return false
+	}
+	_, holiday := b.Holiday(tm)
+	return !holiday
+}
+
+// maxBusinessSearch bounds the days searched for a business day, so a calendar
+// without any does not loop forever
+const maxBusinessSearch = 366 * 10
+
+// AddBusinessDays returns tm moved n business days on the calendar of tm's location,
+// keeping the wall clock. Counting starts from the next day, so one business day
+// after a friday or a saturday is the monday.
+func (b *BusinessCalendar) AddBusinessDays(tm time.Time, n int) (time.Time, error) {
+	dir := 1
+	if n < 0 {
+		dir, n = -1, -n
+	}
+	year, month, day := tm.Date()
+	hour, min, sec := tm.Clock()
+	for searched := 0; n > 0; searched++ {
+		if searched > maxBusinessSearch {
+			return time.Time{}, fmt.Errorf("no business days within %d days of %s", maxBusinessSearch, tm.Format(time.RFC3339))
+		}
+		day += dir
+		tm = time.Date(year, month, day, hour, min, sec, tm.Nanosecond(), tm.Location())
+		if b.IsBusinessDay(tm) {
+			n--
+			searched = 0
+		}
+	}
+	return tm, nil
+}
+
+// BusinessDays counts the business days after the date of start up to and including the date
+// of end, on the calendar of start's location. When end is before start it is minus the business
+// days from the date of end up to the date before start, so BusinessDays(tm, AddBusinessDays(tm, n)) is n.
+func (b *BusinessCalendar) BusinessDays(start, end time.Time) int {
+	from, to := dateOf(start), dateOf(end.In(start.Location()))
+	// forward counts the days after from up to to, backward the days from to up to before from
+	first, last, sign := from.add(1), to, 1
+	if before(to, from) {
+		first, last, sign = to, from.add(-1), -1
+	}
+	count := 0
+	for d := first; !before(last, d); d = d.add(1) {
+		if b.IsBusinessDay(d.noon()) {
+			count++
+		}
+	}
+	return sign * count
+}
+
+// before reports whether date a is before date b
+func before(a, b date) bool {
+	if a.year != b.year {
+		return a.year < b.year
+	}
+	if a.month != b.month {
+		return a.month < b.month
+	}
+	return a.day < b.day
+}
+
+var businessDelta = regexp.MustCompile(`^([+-]?)(\d+)bd$`)
+
+// ParseBusinessDelta reads a delta in business days such as +5bd or -2bd.
+func ParseBusinessDelta(delta string) (int, bool) {
+	m := businessDelta.FindStringSubmatch(strings.TrimSpace(delta))
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[2])
+	if err != nil {
+		return 0, false
+	}
+	if m[1] == "-" {
+		n = -n
+	}
+	return n, true
+}
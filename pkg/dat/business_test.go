@@ -0,0 +1,108 @@
+package dat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWeekend(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []time.Weekday
+		err   bool
+	}{
+		{"sat,sun", []time.Weekday{time.Saturday, time.Sunday}, false},
+		{"fri-sat", []time.Weekday{time.Friday, time.Saturday}, false},
+		{"fri-sun", []time.Weekday{time.Friday, time.Saturday, time.Sunday}, false},
+		{"Sunday", []time.Weekday{time.Sunday}, false},
+		{"none", nil, false},
+		{"sat,holiday", nil, true},
+		{"", nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			got, err := ParseWeekend(test.input)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestParseBusinessDelta(t *testing.T) {
+	for input, want := range map[string]int{"5bd": 5, "+5bd": 5, "-2bd": -2, "0bd": 0} {
+		got, ok := ParseBusinessDelta(input)
+		assert.True(t, ok, input)
+		assert.Equal(t, want, got, input)
+	}
+	for _, input := range []string{"5d", "+2h", "bd", "1.5bd"} {
+		_, ok := ParseBusinessDelta(input)
+		assert.False(t, ok, input)
+	}
+}
+
+func TestBusinessCalendar_AddBusinessDays(t *testing.T) {
+	cal := NewBusinessCalendar()
+	cal.AddHoliday(time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC), "Christmas Day")
+	assert.Equal(t, []time.Weekday{time.Sunday, time.Saturday}, cal.Weekend())
+
+	// a thursday
+	thu := time.Date(2024, 5, 16, 14, 30, 0, 0, time.UTC)
+	sat := time.Date(2024, 5, 18, 9, 0, 0, 0, time.UTC)
+	christmasEve := time.Date(2024, 12, 24, 17, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		tm   time.Time
+		n    int
+		want time.Time
+	}{
+		{"none", thu, 0, thu},
+		{"next day", thu, 1, time.Date(2024, 5, 17, 14, 30, 0, 0, time.UTC)},
+		{"over the weekend", thu, 5, time.Date(2024, 5, 23, 14, 30, 0, 0, time.UTC)},
+		{"from the weekend", sat, 1, time.Date(2024, 5, 20, 9, 0, 0, 0, time.UTC)},
+		{"back over the weekend", thu, -5, time.Date(2024, 5, 9, 14, 30, 0, 0, time.UTC)},
+		{"over a holiday", christmasEve, 1, time.Date(2024, 12, 26, 17, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := cal.AddBusinessDays(test.tm, test.n)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+			assert.Equal(t, test.n, cal.BusinessDays(test.tm, got))
+		})
+	}
+
+	// a middle eastern weekend
+	cal.SetWeekend([]time.Weekday{time.Friday, time.Saturday})
+	got, err := cal.AddBusinessDays(thu, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 19, 14, 30, 0, 0, time.UTC), got)
+
+	// no business days at all
+	cal.SetWeekend([]time.Weekday{0, 1, 2, 3, 4, 5, 6})
+	_, err = cal.AddBusinessDays(thu, 1)
+	assert.Error(t, err)
+}
+
+func TestBusinessCalendar_BusinessDays(t *testing.T) {
+	cal := NewBusinessCalendar()
+	mon := time.Date(2024, 5, 13, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, 0, cal.BusinessDays(mon, mon.Add(time.Hour)))
+	assert.Equal(t, 5, cal.BusinessDays(mon, mon.AddDate(0, 0, 7)))
+	assert.Equal(t, -5, cal.BusinessDays(mon.AddDate(0, 0, 7), mon))
+	// the end is read on the calendar of start
+	la, err := time.LoadLocation(tzLosAngeles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sunday evening in los angeles is monday in utc
+	thu := time.Date(2024, 5, 16, 10, 0, 0, 0, la)
+	sun := time.Date(2024, 5, 19, 20, 0, 0, 0, la)
+	assert.Equal(t, 1, cal.BusinessDays(thu, sun.UTC()))
+	assert.Equal(t, 2, cal.BusinessDays(thu.UTC(), sun))
+}
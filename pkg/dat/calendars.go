@@ -0,0 +1,392 @@
+package dat
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Calendar is a calendar system a date can be shown in
+type Calendar string
+
+const (
+	// CalendarISO ISO 8601 week dates, such as 2023-W46-2
+	CalendarISO Calendar = "iso"
+	// CalendarIslamic the tabular islamic calendar, with the civil epoch and the common leap years
+	CalendarIslamic Calendar = "islamic"
+	// CalendarHebrew the arithmetic hebrew calendar
+	CalendarHebrew Calendar = "hebrew"
+	// CalendarPersian the solar hijri calendar of iran and afghanistan
+	CalendarPersian Calendar = "persian"
+	// CalendarJapanese gregorian dates counted in japanese eras
+	CalendarJapanese Calendar = "japanese"
+	// CalendarBuddhist the thai solar calendar, gregorian dates counted in the buddhist era
+	CalendarBuddhist Calendar = "buddhist"
+	// CalendarChinese the chinese lunisolar calendar, computed from the moon and sun as seen in beijing
+	CalendarChinese Calendar = "chinese"
+)
+
+// Calendars are the supported calendars
+var Calendars = []Calendar{CalendarISO, CalendarIslamic, CalendarHebrew, CalendarPersian, CalendarJapanese,
+	CalendarBuddhist, CalendarChinese}
+
+// calendarAliases are alternate names for calendars
+var calendarAliases = map[string]Calendar{
+	"week":        CalendarISO,
+	"hijri":       CalendarIslamic,
+	"jewish":      CalendarHebrew,
+	"solar-hijri": CalendarPersian,
+	"jalali":      CalendarPersian,
+	"japan":       CalendarJapanese,
+	"thai":        CalendarBuddhist,
+	"lunar":       CalendarChinese,
+}
+
+// ParseCalendar returns the calendar with the given name or alias, case insensitive.
+func ParseCalendar(name string) (Calendar, error) {
+	lower := strings.ToLower(strings.TrimSpace(name))
+	if cal, ok := calendarAliases[lower]; ok {
+		return cal, nil
+	}
+	for _, c := range Calendars {
+		if string(c) == lower {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("unknown calendar %q", name)
+}
+
+// ParseCalendars returns the calendars of names, each a name or alias or a comma separated list of them.
+// "all" is every supported calendar.
+func ParseCalendars(names []string) ([]Calendar, error) {
+	var cals []Calendar
+	for _, list := range names {
+		for _, name := range strings.Split(list, ",") {
+			if strings.EqualFold(strings.TrimSpace(name), "all") {
+				return Calendars, nil
+			}
+			cal, err := ParseCalendar(name)
+			if err != nil {
+				return nil, err
+			}
+			cals = append(cals, cal)
+		}
+	}
+	return cals, nil
+}
+
+// CalendarDate is a date in a calendar
+type CalendarDate struct {
+	Calendar Calendar `json:"calendar"`
+	// Era is the era years are counted in, such as AH or Reiwa, or the sexagenary name of a chinese year
+	Era  string `json:"era,omitempty"`
+	Year int    `json:"year"`
+	// Month counts from the first month of the year, it is 0 for iso week dates
+	Month     int    `json:"month,omitempty"`
+	MonthName string `json:"monthName,omitempty"`
+	// LeapMonth is set for the intercalary month of a chinese year, which repeats the number of the month before
+	LeapMonth bool `json:"leapMonth,omitempty"`
+	// Week is the iso week of the year
+	Week int `json:"week,omitempty"`
+	// Day is the day of the month, or the iso weekday from monday 1 to sunday 7
+	Day int `json:"day"`
+	// Text is the date as it is usually written
+	Text string `json:"text,omitempty"`
+	// Error is why the date could not be computed, such as a time outside the calendar's range
+	Error string `json:"error,omitempty"`
+}
+
+// String implements fmt.Stringer
+func (d CalendarDate) String() string {
+	return d.Text
+}
+
+// ToCalendar returns the date of tm, in its location, in the calendar.
+// Calendars whose days start at sunset are converted by the civil date.
+func ToCalendar(tm time.Time, cal Calendar) (CalendarDate, error) {
+	y, m, d := tm.Date()
+	jdn := julianDayNumber(y, m, d)
+	switch cal {
+	case CalendarISO:
+		year, week := tm.ISOWeek()
+		day := int(tm.Weekday())
+		if day == 0 {
+			day = 7
+		}
+		return CalendarDate{Calendar: cal, Year: year, Week: week, Day: day,
+			Text: fmt.Sprintf("%04d-W%02d-%d", year, week, day)}, nil
+	case CalendarIslamic:
+		return islamicDate(jdn), nil
+	case CalendarHebrew:
+		return hebrewDate(jdn), nil
+	case CalendarPersian:
+		return persianDate(jdn)
+	case CalendarJapanese:
+		return japaneseDate(y, m, d)
+	case CalendarBuddhist:
+		return CalendarDate{Calendar: cal, Era: "BE", Year: y + 543, Month: int(m), MonthName: m.String(), Day: d,
+			Text: fmt.Sprintf("%d %s %d BE", d, m, y+543)}, nil
+	case CalendarChinese:
+		return chineseDate(jdn)
+	}
+	return CalendarDate{}, fmt.Errorf("unknown calendar %q", cal)
+}
+
+// unixJDN is the julian day number of 1970-01-01
+const unixJDN = 2440588
+
+// julianDayNumber is the julian day number of a proleptic gregorian date
+func julianDayNumber(y int, m time.Month, d int) int {
+	days := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400
+	return int(days) + unixJDN
+}
+
+// gregorianDate is the proleptic gregorian date of a julian day number
+func gregorianDate(jdn int) (int, time.Month, int) {
+	return time.Unix(int64(jdn-unixJDN)*86400, 0).UTC().Date()
+}
+
+// floorDiv divides rounding toward negative infinity
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// floorMod is the modulus with the sign of b
+func floorMod(a, b int) int {
+	return a - b*floorDiv(a, b)
+}
+
+// adjustedMod is the modulus in 1 to b instead of 0 to b-1
+func adjustedMod(a, b int) int {
+	return b - floorMod(-a, b)
+}
+
+// islamicEpoch is the julian day number of 1 Muharram 1 AH, 16 July 622 in the julian calendar
+const islamicEpoch = 1948440
+
+var islamicMonths = [12]string{"Muharram", "Safar", "Rabi al-Awwal", "Rabi al-Thani", "Jumada al-Awwal",
+	"Jumada al-Thani", "Rajab", "Shaban", "Ramadan", "Shawwal", "Dhu al-Qadah", "Dhu al-Hijjah"}
+
+// islamicJDN is the julian day number of a tabular islamic date, leap years are the
+// 2nd, 5th, 7th, 10th, 13th, 16th, 18th, 21st, 24th, 26th and 29th of each 30 year cycle
+func islamicJDN(year, month, day int) int {
+	return day + int(math.Ceil(29.5*float64(month-1))) + (year-1)*354 + floorDiv(3+11*year, 30) + islamicEpoch - 1
+}
+
+// islamicDate is the tabular islamic date of a julian day number
+func islamicDate(jdn int) CalendarDate {
+	year := floorDiv(30*(jdn-islamicEpoch)+10646, 10631)
+	month := 1
+	for month < 12 && islamicJDN(year, month+1, 1) <= jdn {
+		month++
+	}
+	day := jdn - islamicJDN(year, month, 1) + 1
+	return CalendarDate{Calendar: CalendarIslamic, Era: "AH", Year: year, Month: month, MonthName: islamicMonths[month-1],
+		Day: day, Text: fmt.Sprintf("%d %s %d AH", day, islamicMonths[month-1], year)}
+}
+
+// hebrewEpoch is the julian day number of 1 Tishrei AM 1, 7 October 3761 BCE in the julian calendar
+const hebrewEpoch = 347998
+
+// hebrew months are numbered from Nisan, the year starts in Tishrei, the 7th month
+const (
+	hebrewNisan  = 1
+	hebrewTishri = 7
+	hebrewAdar   = 12
+	hebrewAdarII = 13
+)
+
+var hebrewMonths = [13]string{"Nisan", "Iyar", "Sivan", "Tammuz", "Av", "Elul", "Tishrei", "Cheshvan", "Kislev",
+	"Tevet", "Shevat", "Adar", "Adar II"}
+
+// hebrewLeapYear reports whether the year has a 13th month, 7 years of each 19 year cycle do
+func hebrewLeapYear(year int) bool {
+	return floorMod(7*year+1, 19) < 7
+}
+
+// hebrewElapsedDays are the days from the epoch to the molad of Tishrei, delayed when
+// the molad falls on a sunday, wednesday or friday
+func hebrewElapsedDays(year int) int {
+	months := floorDiv(235*year-234, 19)
+	parts := 12084 + 13753*months
+	days := 29*months + floorDiv(parts, 25920)
+	if floorMod(3*(days+1), 7) < 3 {
+		return days + 1
+	}
+	return days
+}
+
+// hebrewNewYear is the julian day number of 1 Tishrei, delayed so years have an allowed length
+func hebrewNewYear(year int) int {
+	ny0, ny1, ny2 := hebrewElapsedDays(year-1), hebrewElapsedDays(year), hebrewElapsedDays(year+1)
+	correction := 0
+	switch {
+	case ny2-ny1 == 356:
+		correction = 2
+	case ny1-ny0 == 382:
+		correction = 1
+	}
+	return hebrewEpoch + ny1 + correction
+}
+
+// hebrewMonthDays is the number of days of the month
+func hebrewMonthDays(year, month int) int {
+	yearDays := hebrewNewYear(year+1) - hebrewNewYear(year)
+	switch {
+	case month == 2, month == 4, month == 6, month == 10, month == hebrewAdarII,
+		month == hebrewAdar && !hebrewLeapYear(year),
+		month == 8 && yearDays%10 != 5,
+		month == 9 && yearDays%10 == 3:
+		return 29
+	}
+	return 30
+}
+
+// hebrewMonthsOfYear are the months of the year in order, from Tishrei
+func hebrewMonthsOfYear(year int) []int {
+	last := hebrewAdar
+	if hebrewLeapYear(year) {
+		last = hebrewAdarII
+	}
+	var months []int
+	for m := hebrewTishri; m <= last; m++ {
+		months = append(months, m)
+	}
+	for m := hebrewNisan; m < hebrewTishri; m++ {
+		months = append(months, m)
+	}
+	return months
+}
+
+// hebrewDate is the hebrew date of a julian day number
+func hebrewDate(jdn int) CalendarDate {
+	year := int(float64(jdn-hebrewEpoch)/365.2468) + 1
+	for hebrewNewYear(year+1) <= jdn {
+		year++
+	}
+	for hebrewNewYear(year) > jdn {
+		year--
+	}
+	day := jdn - hebrewNewYear(year) + 1
+	month := hebrewTishri
+	for _, m := range hebrewMonthsOfYear(year) {
+		month = m
+		if n := hebrewMonthDays(year, m); day > n {
+			day -= n
+			continue
+		}
+		break
+	}
+	name := hebrewMonths[month-1]
+	if month == hebrewAdar && hebrewLeapYear(year) {
+		name = "Adar I"
+	}
+	return CalendarDate{Calendar: CalendarHebrew, Era: "AM", Year: year, Month: month, MonthName: name, Day: day,
+		Text: fmt.Sprintf("%d %s %d", day, name, year)}
+}
+
+var persianMonths = [12]string{"Farvardin", "Ordibehesht", "Khordad", "Tir", "Mordad", "Shahrivar", "Mehr", "Aban",
+	"Azar", "Dey", "Bahman", "Esfand"}
+
+// persianBreaks are the years the 33 year leap cycles of the solar hijri calendar are
+// broken to follow the march equinox, after Borkowski
+var persianBreaks = []int{-61, 9, 38, 199, 426, 686, 756, 818, 1111, 1181, 1210, 1635, 2060, 2097, 2192, 2262,
+	2324, 2394, 2456, 3178}
+
+// persianNewYear is the day of march of 1 Farvardin, after the leap days of the cycles
+// since the first break year
+func persianNewYear(year int) (int, error) {
+	if year < persianBreaks[0] || year >= persianBreaks[len(persianBreaks)-1] {
+		return 0, fmt.Errorf("persian year %d is outside %d to %d", year, persianBreaks[0], persianBreaks[len(persianBreaks)-1]-1)
+	}
+	gy := year + 621
+	leapJ := -14
+	jp := persianBreaks[0]
+	jump := 0
+	for _, jm := range persianBreaks[1:] {
+		jump = jm - jp
+		if year < jm {
+			break
+		}
+		leapJ += jump/33*8 + jump%33/4
+		jp = jm
+	}
+	n := year - jp
+	leapJ += n/33*8 + (n%33+3)/4
+	if jump%33 == 4 && jump-n == 4 {
+		leapJ++
+	}
+	leapG := gy/4 - (gy/100+1)*3/4 - 150
+	return 20 + leapJ - leapG, nil
+}
+
+// persianDate is the solar hijri date of a julian day number
+func persianDate(jdn int) (CalendarDate, error) {
+	gy, _, _ := gregorianDate(jdn)
+	year := gy - 621
+	march, err := persianNewYear(year)
+	if err != nil {
+		return CalendarDate{}, err
+	}
+	k := jdn - julianDayNumber(gy, time.March, march)
+	if k < 0 {
+		year--
+		if march, err = persianNewYear(year); err != nil {
+			return CalendarDate{}, err
+		}
+		k = jdn - julianDayNumber(gy-1, time.March, march)
+	}
+	// the first six months have 31 days, the next five 30 and Esfand 29 or 30
+	month, day := 1+k/31, k%31+1
+	if k > 185 {
+		month, day = 7+(k-186)/30, (k-186)%30+1
+	}
+	return CalendarDate{Calendar: CalendarPersian, Era: "AP", Year: year, Month: month, MonthName: persianMonths[month-1],
+		Day: day, Text: fmt.Sprintf("%d %s %d AP", day, persianMonths[month-1], year)}, nil
+}
+
+// japaneseEra is an era and its first day
+type japaneseEra struct {
+	name, kanji string
+	start       time.Time
+}
+
+// japaneseEras are the eras since japan adopted the gregorian calendar, latest first
+var japaneseEras = []japaneseEra{
+	{"Reiwa", "令和", time.Date(2019, time.May, 1, 0, 0, 0, 0, time.UTC)},
+	{"Heisei", "平成", time.Date(1989, time.January, 8, 0, 0, 0, 0, time.UTC)},
+	{"Showa", "昭和", time.Date(1926, time.December, 25, 0, 0, 0, 0, time.UTC)},
+	{"Taisho", "大正", time.Date(1912, time.July, 30, 0, 0, 0, 0, time.UTC)},
+	{"Meiji", "明治", time.Date(1868, time.January, 25, 0, 0, 0, 0, time.UTC)},
+}
+
+// japaneseGregorian is when japan adopted the gregorian calendar, Meiji 6
+var japaneseGregorian = time.Date(1873, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// japaneseDate is the gregorian date counted in the japanese era
+func japaneseDate(y int, m time.Month, d int) (CalendarDate, error) {
+	date := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if date.Before(japaneseGregorian) {
+		return CalendarDate{}, fmt.Errorf("japanese dates start in %d, when japan adopted the gregorian calendar", japaneseGregorian.Year())
+	}
+	for _, era := range japaneseEras {
+		if date.Before(era.start) {
+			continue
+		}
+		year := y - era.start.Year() + 1
+		// the first year of an era is written 元年
+		kanjiYear := strconv.Itoa(year)
+		if year == 1 {
+			kanjiYear = "元"
+		}
+		return CalendarDate{Calendar: CalendarJapanese, Era: era.name, Year: year, Month: int(m), MonthName: m.String(), Day: d,
+			Text: fmt.Sprintf("%s %d-%02d-%02d (%s%s年%d月%d日)", era.name, year, m, d, era.kanji, kanjiYear, m, d)}, nil
+	}
+	return CalendarDate{}, fmt.Errorf("no japanese era for %s", date.Format("2006-01-02"))
+}
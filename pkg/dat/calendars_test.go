@@ -0,0 +1,114 @@
+package dat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCalendar(t *testing.T) {
+	tests := []struct {
+		name  string
+		want  Calendar
+		error bool
+	}{
+		{"iso", CalendarISO, false},
+		{"Hijri", CalendarIslamic, false},
+		{"jalali", CalendarPersian, false},
+		{"thai", CalendarBuddhist, false},
+		{"CHINESE", CalendarChinese, false},
+		{"mayan", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseCalendar(test.name)
+			if test.error {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestParseCalendars(t *testing.T) {
+	cals, err := ParseCalendars([]string{"hebrew,jalali", "iso"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Calendar{CalendarHebrew, CalendarPersian, CalendarISO}, cals)
+	cals, err = ParseCalendars([]string{"iso", "All"})
+	assert.NoError(t, err)
+	assert.Equal(t, Calendars, cals)
+	cals, err = ParseCalendars(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, cals)
+	_, err = ParseCalendars([]string{"iso,mayan"})
+	assert.Error(t, err)
+}
+
+func TestToCalendar(t *testing.T) {
+	tests := []struct {
+		date     string
+		calendar Calendar
+		want     string
+	}{
+		{"2023-11-14", CalendarISO, "2023-W46-2"},
+		{"2021-01-03", CalendarISO, "2020-W53-7"},
+		{"2023-07-19", CalendarIslamic, "1 Muharram 1445 AH"},
+		{"2024-03-11", CalendarIslamic, "1 Ramadan 1445 AH"},
+		{"0622-07-19", CalendarIslamic, "1 Muharram 1 AH"},
+		{"2023-09-16", CalendarHebrew, "1 Tishrei 5784"},
+		{"2023-11-14", CalendarHebrew, "1 Kislev 5784"},
+		{"2024-02-10", CalendarHebrew, "1 Adar I 5784"},
+		{"2024-03-11", CalendarHebrew, "1 Adar II 5784"},
+		{"2023-03-21", CalendarHebrew, "28 Adar 5783"},
+		{"2023-03-21", CalendarPersian, "1 Farvardin 1402 AP"},
+		{"2024-03-20", CalendarPersian, "1 Farvardin 1403 AP"},
+		{"2024-03-19", CalendarPersian, "29 Esfand 1402 AP"},
+		{"2025-03-20", CalendarPersian, "30 Esfand 1403 AP"},
+		{"2023-11-14", CalendarPersian, "23 Aban 1402 AP"},
+		{"2019-04-30", CalendarJapanese, "Heisei 31-04-30 (平成31年4月30日)"},
+		{"2019-05-01", CalendarJapanese, "Reiwa 1-05-01 (令和元年5月1日)"},
+		{"1989-01-07", CalendarJapanese, "Showa 64-01-07 (昭和64年1月7日)"},
+		{"2023-11-14", CalendarBuddhist, "14 November 2566 BE"},
+		{"2023-01-21", CalendarChinese, "壬寅 year of the Water Tiger, month 12, day 30"},
+		{"2023-01-22", CalendarChinese, "癸卯 year of the Water Rabbit, month 1, day 1"},
+		{"2023-03-22", CalendarChinese, "癸卯 year of the Water Rabbit, leap month 2, day 1"},
+		{"2023-11-14", CalendarChinese, "癸卯 year of the Water Rabbit, month 10, day 2"},
+		{"2020-05-23", CalendarChinese, "庚子 year of the Metal Rat, leap month 4, day 1"},
+		{"2033-12-22", CalendarChinese, "癸丑 year of the Water Ox, leap month 11, day 1"},
+		{"1985-02-20", CalendarChinese, "乙丑 year of the Wood Ox, month 1, day 1"},
+	}
+	for _, test := range tests {
+		t.Run(string(test.calendar)+" "+test.date, func(t *testing.T) {
+			tm, err := time.Parse("2006-01-02", test.date)
+			assert.NoError(t, err)
+			got, err := ToCalendar(tm, test.calendar)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got.String())
+		})
+	}
+
+	// the date is that of the location
+	tm := time.Date(2023, time.November, 13, 23, 0, 0, 0, time.UTC)
+	got, err := ToCalendar(tm.In(time.FixedZone("UTC+8", 8*3600)), CalendarHebrew)
+	assert.NoError(t, err)
+	assert.Equal(t, CalendarDate{Calendar: CalendarHebrew, Era: "AM", Year: 5784, Month: 9, MonthName: "Kislev", Day: 1,
+		Text: "1 Kislev 5784"}, got)
+
+	for _, test := range []struct {
+		date     string
+		calendar Calendar
+	}{
+		{"1872-12-31", CalendarJapanese},
+		{"1600-01-01", CalendarChinese},
+		{"4000-01-01", CalendarPersian},
+	} {
+		tm, _ := time.Parse("2006-01-02", test.date)
+		_, err := ToCalendar(tm, test.calendar)
+		assert.Error(t, err, test.date)
+	}
+	_, err = ToCalendar(tm, "mayan")
+	assert.Error(t, err)
+}
@@ -0,0 +1,222 @@
+package dat
+
+import (
+	"fmt"
+	"math"
+)
+
+// The chinese calendar follows the rules of Calendrical Calculations: months start on the day of
+// a new moon in beijing, the month with the winter solstice is the 11th, and in a year of 13 months
+// between solstices the first month without a major solar term is a leap month. The sun and moon
+// are placed with the lower precision series of Meeus, Astronomical Algorithms, accurate to minutes.
+
+// synodicMonth is the mean time between new moons in days
+const synodicMonth = 29.530588861
+
+// tropicalYear is the mean time between march equinoxes in days
+const tropicalYear = 365.242189
+
+// j2000 is the julian date of 2000-01-01 12:00 TT
+const j2000 = 2451545.0
+
+// chinaOffset is the offset of china standard time in days
+const chinaOffset = 8.0 / 24
+
+// chineseYears bounds the years of chinese dates, the calendar took its modern rules in 1645
+var chineseYears = [2]int{1645, 2500}
+
+var chineseStems = [10]string{"甲", "乙", "丙", "丁", "戊", "己", "庚", "辛", "壬", "癸"}
+var chineseBranches = [12]string{"子", "丑", "寅", "卯", "辰", "巳", "午", "未", "申", "酉", "戌", "亥"}
+var chineseElements = [5]string{"Wood", "Fire", "Earth", "Metal", "Water"}
+var chineseAnimals = [12]string{"Rat", "Ox", "Tiger", "Rabbit", "Dragon", "Snake", "Horse", "Goat", "Monkey",
+	"Rooster", "Dog", "Pig"}
+
+// chineseDate is the chinese date of a julian day number, as a day in china
+func chineseDate(jdn int) (CalendarDate, error) {
+	gy, gm, _ := gregorianDate(jdn)
+	if gy < chineseYears[0] || gy > chineseYears[1] {
+		return CalendarDate{}, fmt.Errorf("chinese dates are computed from %d to %d", chineseYears[0], chineseYears[1])
+	}
+	s1 := winterSolsticeOnOrBefore(jdn)
+	s2 := winterSolsticeOnOrBefore(s1 + 370)
+	m12 := chineseNewMoonOnOrAfter(s1 + 1)
+	nextM11 := chineseNewMoonBefore(s2 + 1)
+	m := chineseNewMoonBefore(jdn + 1)
+	leapYear := math.Round(float64(nextM11-m12)/synodicMonth) == 12
+
+	month := int(math.Round(float64(m-m12) / synodicMonth))
+	if leapYear && priorLeapMonth(m12, m) {
+		month--
+	}
+	month = adjustedMod(month, 12)
+	leapMonth := leapYear && noMajorSolarTerm(m) && !priorLeapMonth(m12, chineseNewMoonBefore(m))
+	day := jdn - m + 1
+
+	// months 11 and 12 in january or february belong to the year before
+	year := gy
+	if month >= 11 && gm <= 2 {
+		year--
+	}
+	cycle := floorMod(year-4, 60)
+	era := chineseStems[cycle%10] + chineseBranches[cycle%12]
+	name := fmt.Sprintf("month %d", month)
+	if leapMonth {
+		name = fmt.Sprintf("leap month %d", month)
+	}
+	return CalendarDate{Calendar: CalendarChinese, Era: era, Year: year, Month: month, MonthName: name,
+		LeapMonth: leapMonth, Day: day,
+		Text: fmt.Sprintf("%s year of the %s %s, %s, day %d", era, chineseElements[cycle%10/2], chineseAnimals[cycle%12], name, day)}, nil
+}
+
+// chinaDay is the julian day number of the day in china of a julian date
+func chinaDay(jd float64) int {
+	return int(math.Floor(jd + 0.5 + chinaOffset))
+}
+
+// chinaMidnight is the julian date of the start of the day in china
+func chinaMidnight(jdn int) float64 {
+	return float64(jdn) - 0.5 - chinaOffset
+}
+
+// chineseNewMoonOnOrAfter is the day in china of the first new moon on or after the day
+func chineseNewMoonOnOrAfter(jdn int) int {
+	return chinaDay(newMoonAtOrAfter(chinaMidnight(jdn)))
+}
+
+// chineseNewMoonBefore is the day in china of the last new moon before the day
+func chineseNewMoonBefore(jdn int) int {
+	return chinaDay(newMoonBefore(chinaMidnight(jdn)))
+}
+
+// winterSolsticeOnOrBefore is the day in china of the last winter solstice on or before the day
+func winterSolsticeOnOrBefore(jdn int) int {
+	return chinaDay(solarLongitudeBefore(270, chinaMidnight(jdn+1)))
+}
+
+// majorSolarTerm is the major solar term, 1 to 12, at the start of the day in china
+func majorSolarTerm(jdn int) int {
+	return adjustedMod(2+int(math.Floor(solarLongitude(chinaMidnight(jdn))/30)), 12)
+}
+
+// noMajorSolarTerm reports whether no major solar term starts in the month starting on the day
+func noMajorSolarTerm(jdn int) bool {
+	return majorSolarTerm(jdn) == majorSolarTerm(chineseNewMoonOnOrAfter(jdn+1))
+}
+
+// priorLeapMonth reports whether there is a leap month from the month starting on first up to the one starting on m
+func priorLeapMonth(first, m int) bool {
+	for ; m >= first; m = chineseNewMoonBefore(m) {
+		if noMajorSolarTerm(m) {
+			return true
+		}
+	}
+	return false
+}
+
+// deltaT is TT - UT in days, from the long term parabola of Morrison and Stephenson,
+// within a couple of minutes for the years of chinese dates
+func deltaT(jd float64) float64 {
+	u := ((jd-j2000)/36525*100 + 2000 - 1820) / 100
+	return (-20 + 32*u*u) / 86400
+}
+
+// sinDeg is the sine of degrees
+func sinDeg(d float64) float64 { return math.Sin(d * math.Pi / 180) }
+
+// solarLongitude is the apparent geocentric longitude of the sun in degrees at a julian date in UT
+func solarLongitude(jd float64) float64 {
+	t := (jd + deltaT(jd) - j2000) / 36525
+	l0 := 280.46646 + 36000.76983*t + 0.0003032*t*t
+	m := 357.52911 + 35999.05029*t - 0.0001537*t*t
+	c := (1.914602-0.004817*t-0.000014*t*t)*sinDeg(m) + (0.019993-0.000101*t)*sinDeg(2*m) + 0.000289*sinDeg(3*m)
+	omega := 125.04 - 1934.136*t
+	lambda := l0 + c - 0.00569 - 0.00478*sinDeg(omega)
+	return math.Mod(math.Mod(lambda, 360)+360, 360)
+}
+
+// solarLongitudeBefore is the last julian date before jd the sun reaches the longitude
+func solarLongitudeBefore(longitude, jd float64) float64 {
+	rate := tropicalYear / 360
+	t := jd - rate*math.Mod(solarLongitude(jd)-longitude+360, 360)
+	for i := 0; i < 5; i++ {
+		diff := math.Mod(solarLongitude(t)-longitude+540, 360) - 180
+		t -= diff * rate
+	}
+	return t
+}
+
+// newMoon is the julian date in UT of the new moon k lunations after that of 2000-01-06
+func newMoon(k float64) float64 {
+	t := k / 1236.85
+	jde := 2451550.09766 + synodicMonth*k + 0.00015437*t*t - 0.000000150*t*t*t + 0.00000000073*t*t*t*t
+	e := 1 - 0.002516*t - 0.0000074*t*t
+	m := 2.5534 + 29.10535670*k - 0.0000014*t*t - 0.00000011*t*t*t
+	mp := 201.5643 + 385.81693528*k + 0.0107582*t*t + 0.00001238*t*t*t - 0.000000058*t*t*t*t
+	f := 160.7108 + 390.67050284*k - 0.0016118*t*t - 0.00000227*t*t*t + 0.000000011*t*t*t*t
+	omega := 124.7746 - 1.56375588*k + 0.0020672*t*t + 0.00000215*t*t*t
+
+	jde += -0.40720*sinDeg(mp) +
+		0.17241*e*sinDeg(m) +
+		0.01608*sinDeg(2*mp) +
+		0.01039*sinDeg(2*f) +
+		0.00739*e*sinDeg(mp-m) -
+		0.00514*e*sinDeg(mp+m) +
+		0.00208*e*e*sinDeg(2*m) -
+		0.00111*sinDeg(mp-2*f) -
+		0.00057*sinDeg(mp+2*f) +
+		0.00056*e*sinDeg(2*mp+m) -
+		0.00042*sinDeg(3*mp) +
+		0.00042*e*sinDeg(m+2*f) +
+		0.00038*e*sinDeg(m-2*f) -
+		0.00024*e*sinDeg(2*mp-m) -
+		0.00017*sinDeg(omega) -
+		0.00007*sinDeg(mp+2*m) +
+		0.00004*sinDeg(2*mp-2*f) +
+		0.00004*sinDeg(3*m) +
+		0.00003*sinDeg(mp+m-2*f) +
+		0.00003*sinDeg(2*mp+2*f) -
+		0.00003*sinDeg(mp+m+2*f) +
+		0.00003*sinDeg(mp-m+2*f) -
+		0.00002*sinDeg(mp-m-2*f) -
+		0.00002*sinDeg(3*mp+m) +
+		0.00002*sinDeg(4*mp)
+
+	// the planetary arguments
+	for _, a := range [][2]float64{
+		{0.000325, 299.77 + 0.107408*k - 0.009173*t*t},
+		{0.000165, 251.88 + 0.016321*k},
+		{0.000164, 251.83 + 26.651886*k},
+		{0.000126, 349.42 + 36.412478*k},
+		{0.000110, 84.66 + 18.206239*k},
+		{0.000062, 141.74 + 53.303771*k},
+		{0.000060, 207.14 + 2.453732*k},
+		{0.000056, 154.84 + 7.306860*k},
+		{0.000047, 34.52 + 27.261239*k},
+		{0.000042, 207.19 + 0.121824*k},
+		{0.000040, 291.34 + 1.844379*k},
+		{0.000037, 161.72 + 24.198154*k},
+		{0.000035, 239.56 + 25.513099*k},
+		{0.000023, 331.55 + 3.592518*k},
+	} {
+		jde += a[0] * sinDeg(a[1])
+	}
+	return jde - deltaT(jde)
+}
+
+// newMoonAtOrAfter is the julian date of the first new moon at or after jd
+func newMoonAtOrAfter(jd float64) float64 {
+	k := math.Floor((jd-2451550.09766)/synodicMonth) - 1
+	for newMoon(k) < jd {
+		k++
+	}
+	return newMoon(k)
+}
+
+// newMoonBefore is the julian date of the last new moon before jd
+func newMoonBefore(jd float64) float64 {
+	k := math.Floor((jd-2451550.09766)/synodicMonth) + 1
+	for newMoon(k) >= jd {
+		k--
+	}
+	return newMoon(k)
+}
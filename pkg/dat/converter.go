@@ -0,0 +1,598 @@
+package dat
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Precision is the unit of an epoch.
+type Precision int
+
+const (
+	// Seconds epochs in seconds
+	Seconds Precision = iota
+	// Milliseconds epochs in milliseconds
+	Milliseconds
+)
+
+// String implements fmt.Stringer
+func (p Precision) String() string {
+	switch p {
+	case Milliseconds:
+		return "ms"
+	default:
+		return "s"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (p Precision) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (p *Precision) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "s":
+		*p = Seconds
+	case "ms":
+		*p = Milliseconds
+	default:
+		return fmt.Errorf("unknown precision %q", text)
+	}
+	return nil
+}
+
+// Converter parses input and builds results using its configured options.
+// The zero value is not usable, use NewConverter.
+type Converter struct {
+	precision   Precision
+	format      string
+	dialect     Dialect
+	locale      *Locale
+	delta       string
+	zone        string
+	timeFormats bool
+
+	minYear, maxYear       int
+	hasMinYear, hasMaxYear bool
+
+	idKind         IDKind
+	snowflakeEpoch int64
+
+	base, outputBase EpochBase
+
+	scale, outputScale Scale
+
+	radix     int
+	byteOrder binary.ByteOrder
+
+	snapMode SnapMode
+	snapUnit Unit
+
+	business *BusinessCalendar
+
+	calendars   []Calendar
+	offsetZones bool
+	epochs      bool
+	bases       bool
+	metadata    bool
+}
+
+// Option configures a Converter
+type Option func(*Converter)
+
+// WithPrecision sets the precision of epoch input and output.
+func WithPrecision(p Precision) Option {
+	return func(c *Converter) {
+		c.precision = p
+	}
+}
+
+// WithFormat sets the output layout, either a time layout or a supported format name.
+func WithFormat(format string) Option {
+	return func(c *Converter) {
+		c.format = format
+	}
+}
+
+// WithDialect sets the dialect the format is written in, see TranslateLayout.
+func WithDialect(d Dialect) Option {
+	return func(c *Converter) {
+		c.dialect = d
+	}
+}
+
+// WithLocale sets the month and weekday names of output and input and the default layout,
+// see FormatLocale and ParseTimeLocale. Without it names are in english.
+func WithLocale(l *Locale) Option {
+	return func(c *Converter) {
+		c.locale = l
+	}
+}
+
+// WithDelta sets a duration (see time.ParseDuration) added to every result,
+// or a number of business days such as +5bd, see WithBusinessCalendar.
+func WithDelta(delta string) Option {
+	return func(c *Converter) {
+		c.delta = delta
+	}
+}
+
+// WithZone sets a zone to include in every result, a tz database name, a fixed offset
+// or a POSIX TZ string, see LoadZone.
+func WithZone(zone string) Option {
+	return func(c *Converter) {
+		c.zone = zone
+	}
+}
+
+// WithTimeFormats parses input as one of the supported time formats instead of an epoch.
+// The format, in its dialect, is tried first when it has layout elements.
+func WithTimeFormats(enabled bool) Option {
+	return func(c *Converter) {
+		c.timeFormats = enabled
+	}
+}
+
+// WithMinYear rejects input before the given year, in UTC.
+func WithMinYear(year int) Option {
+	return func(c *Converter) {
+		c.minYear, c.hasMinYear = year, true
+	}
+}
+
+// WithMaxYear rejects input after the given year, in UTC.
+func WithMaxYear(year int) Option {
+	return func(c *Converter) {
+		c.maxYear, c.hasMaxYear = year, true
+	}
+}
+
+// WithID parses input as an id of the given kind. Without it, input that is not
+// an epoch is decoded as any id kind it resembles, and integers of 17 digits or more,
+// which are not plausible epochs, as snowflakes.
+func WithID(kind IDKind) Option {
+	return func(c *Converter) {
+		c.idKind = kind
+	}
+}
+
+// WithSnowflakeEpoch sets the epoch of snowflake ids in unix milliseconds.
+func WithSnowflakeEpoch(ms int64) Option {
+	return func(c *Converter) {
+		c.snowflakeEpoch = ms
+	}
+}
+
+// WithEpochBase reads epochs in the given base instead of unix time.
+// The precision only applies to unix epochs.
+func WithEpochBase(base EpochBase) Option {
+	return func(c *Converter) {
+		c.base = base
+	}
+}
+
+// WithOutputBase includes the time in the given epoch base in every result.
+func WithOutputBase(base EpochBase) Option {
+	return func(c *Converter) {
+		c.outputBase = base
+	}
+}
+
+// WithScale reads input as a clock in the given time scale instead of UTC.
+// It does not apply to gps epochs, which are always in the gps scale.
+func WithScale(s Scale) Option {
+	return func(c *Converter) {
+		c.scale = s
+	}
+}
+
+// WithOutputScale shows results as a clock in the given time scale instead of UTC.
+func WithOutputScale(s Scale) Option {
+	return func(c *Converter) {
+		c.outputScale = s
+	}
+}
+
+// WithRadix reads and writes epochs in the given radix, 2 to 36. The default 0 reads decimal
+// or the radix of a 0x, 0o or 0b prefix and writes decimal.
+func WithRadix(radix int) Option {
+	return func(c *Converter) {
+		c.radix = radix
+	}
+}
+
+// WithBytes reads epochs as raw 32 or 64 bit fields in the byte order, written as hex.
+func WithBytes(order binary.ByteOrder) Option {
+	return func(c *Converter) {
+		c.byteOrder = order
+	}
+}
+
+// WithSnap snaps every result to a boundary of the unit, after the delta.
+// Boundaries are those of the zone when one is set, and of the time's location otherwise.
+func WithSnap(mode SnapMode, unit Unit) Option {
+	return func(c *Converter) {
+		c.snapMode, c.snapUnit = mode, unit
+	}
+}
+
+// WithBusinessCalendar sets the weekend and holidays of business day deltas.
+// Without it the DefaultWeekend is used and there are no holidays.
+func WithBusinessCalendar(cal *BusinessCalendar) Option {
+	return func(c *Converter) {
+		c.business = cal
+	}
+}
+
+// WithOffsetZones includes the tz database zones that currently share the offset of a fixed
+// offset zone in every result, see ZonesAtOffset.
+func WithOffsetZones(enabled bool) Option {
+	return func(c *Converter) {
+		c.offsetZones = enabled
+	}
+}
+
+// WithEpochs includes the time as a unix epoch in every unit in every result,
+// as read in the output scale, and in the epoch base and output base when they are not unix.
+func WithEpochs(enabled bool) Option {
+	return func(c *Converter) {
+		c.epochs = enabled
+	}
+}
+
+// WithBases includes the time in every supported epoch base in every result.
+func WithBases(enabled bool) Option {
+	return func(c *Converter) {
+		c.bases = enabled
+	}
+}
+
+// WithMetadata includes the metadata of the time in the local, utc and zone clocks in every
+// result, as read in the output scale. The unix epochs are those of the time.
+func WithMetadata(enabled bool) Option {
+	return func(c *Converter) {
+		c.metadata = enabled
+	}
+}
+
+// WithCalendars includes the date in each calendar in every result.
+// Dates are those of the zone when one is set, and of the time's location otherwise.
+func WithCalendars(cals ...Calendar) Option {
+	return func(c *Converter) {
+		c.calendars = cals
+	}
+}
+
+// NewConverter creates a new Converter with the given options applied.
+func NewConverter(opts ...Option) *Converter {
+	c := &Converter{snowflakeEpoch: DefaultSnowflakeEpoch, base: BaseUnix, outputBase: BaseUnix,
+		scale: ScaleUTC, outputScale: ScaleUTC}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Result is a converted time.
+type Result struct {
+	// Time is the converted instant, delta included.
+	Time time.Time `json:"time"`
+	// Scale is the time scale of Epoch and the formatted values, empty for utc.
+	Scale Scale `json:"scale,omitempty"`
+	// Epoch is Time as an epoch in Precision.
+	Epoch     int64     `json:"epoch"`
+	Precision Precision `json:"precision"`
+	// Formatted is Time in its own location.
+	Formatted string `json:"formatted"`
+	Local     string `json:"local"`
+	UTC       string `json:"utc"`
+	// Zone is Time in ZoneName, empty when no zone was requested or it could not be loaded.
+	Zone     string `json:"zone,omitempty"`
+	ZoneName string `json:"zoneName,omitempty"`
+	// OffsetZones are the tz database zones currently at the offset of a fixed offset zone,
+	// see WithOffsetZones.
+	OffsetZones []string `json:"offsetZones,omitempty"`
+	// DetectedFormat is the name of the time format the input was parsed with, if any.
+	DetectedFormat string `json:"detected,omitempty"`
+	// ID is the identifier the time was extracted from, if any.
+	ID *ID `json:"id,omitempty"`
+	// BaseValue is Time in OutputBase, empty for unix.
+	BaseValue  string    `json:"baseValue,omitempty"`
+	OutputBase EpochBase `json:"outputBase,omitempty"`
+	// RadixValue is Epoch in Radix, empty for decimal.
+	RadixValue string `json:"radixValue,omitempty"`
+	Radix      int    `json:"radix,omitempty"`
+	// Epochs is Time as a unix epoch in every unit, see WithEpochs.
+	Epochs []EpochValue `json:"epochs,omitempty"`
+	// EpochBases is Time in the configured epoch bases other than unix, listed with Epochs.
+	EpochBases []BaseValue `json:"epochBases,omitempty"`
+	// Bases is Time in every supported epoch base, see WithBases.
+	Bases []BaseValue `json:"bases,omitempty"`
+	// LeapSecond is the leap second Time is on or near, if any.
+	LeapSecond *LeapSecondNote `json:"leapSecond,omitempty"`
+	// Warnings flag a suspicious input, see Converter.Check.
+	Warnings []Warning `json:"warnings,omitempty"`
+	// Metadata describes Time in each clock, see WithMetadata.
+	Metadata *TimeMetadata `json:"metadata,omitempty"`
+	// Calendars is the date in each requested calendar.
+	Calendars []CalendarDate `json:"calendars,omitempty"`
+}
+
+// Epoch returns tm as an epoch in the configured precision.
+// Times that overflow the precision are clamped, see Converter.Check.
+func (c *Converter) Epoch(tm time.Time) int64 {
+	if !epochFits(tm, c.precision) {
+		if tm.Unix() < 0 {
+			return math.MinInt64
+		}
+		return math.MaxInt64
+	}
+	if c.precision == Milliseconds {
+		return tm.UnixMilli()
+	}
+	return tm.Unix()
+}
+
+// Parsed is input converted to a time
+type Parsed struct {
+	Time time.Time
+	// DetectedFormat is the name of the time format the input was parsed with, if any.
+	DetectedFormat string
+	// ID is the identifier the time was extracted from, if any.
+	ID *ID
+	// LeapSecond is the leap second the input is on or near, if any.
+	LeapSecond *LeapSecondNote
+}
+
+// Parse converts the input to a time.
+func (c *Converter) Parse(input string) (*Parsed, error) {
+	p := &Parsed{}
+	var err error
+	switch {
+	case c.idKind != "":
+		Logger().Info("parsing input as an id", "input", input, "kind", c.idKind)
+		p.ID, err = ParseID(input, c.idKind, c.snowflakeEpoch)
+	case c.timeFormats:
+		Logger().Info("parsing input as a time format", "input", input)
+		if layout, ok := c.parseLayout(); ok {
+			tm, perr := parseLocale(layout, input, c.locale)
+			if perr == nil {
+				Logger().Info("input matched the format", "input", input, "format", c.format, "layout", layout)
+				p.Time, p.DetectedFormat = tm, c.format
+				if name, ok := FormatName(layout); ok {
+					p.DetectedFormat = name
+				}
+				break
+			}
+			Logger().Debug("format did not match", "format", c.format, "layout", layout, "err", perr)
+		}
+		var f namedFormat
+		p.Time, f, err = parseTime(input, c.locale)
+		p.DetectedFormat = f.name
+	case c.base != BaseUnix:
+		Logger().Info("parsing input in an epoch base", "input", input, "base", c.base)
+		p.Time, err = FromEpochBase(input, c.base)
+	case c.byteOrder != nil:
+		Logger().Info("parsing input as epoch bytes", "input", input, "precision", c.precision, "order", c.byteOrder)
+		p.Time, err = ParseEpochBytes(input, c.byteOrder, c.precision == Milliseconds)
+	default:
+		Logger().Info("parsing input as an epoch", "input", input, "precision", c.precision, "radix", c.radix)
+		p.Time, err = ParseEpochRadix(input, c.radix, c.precision == Milliseconds)
+		if errors.Is(err, ErrNotAnEpoch) {
+			if id, idErr := DetectID(input); idErr == nil {
+				Logger().Info("input is not an epoch, detected an id", "input", input, "kind", id.Kind)
+				p.ID, err = id, nil
+			} else {
+				Logger().Debug("input is not an id", "input", input, "err", idErr)
+			}
+		} else if c.radix == 0 && (err == nil || errors.Is(err, ErrOutOfRange)) {
+			if id, ok := detectSnowflake(input, c.snowflakeEpoch); ok {
+				Logger().Info("input is too long for a plausible epoch, detected a snowflake", "input", input, "epoch", c.snowflakeEpoch)
+				p.ID, err = id, nil
+			}
+		}
+	}
+	if err != nil {
+		Logger().Info("input could not be parsed", "input", input, "err", err)
+		return nil, err
+	}
+	var onLeap bool
+	switch {
+	case p.ID != nil:
+		p.Time = p.ID.Time
+	case c.base != BaseGPS:
+		p.Time, onLeap = FromScale(p.Time, c.scale)
+		if c.scale != ScaleUTC {
+			Logger().Info("input read as a clock in a time scale", "scale", c.scale, "instant", p.Time)
+		}
+	}
+	if p.LeapSecond = NearLeapSecond(p.Time); p.LeapSecond != nil {
+		p.LeapSecond.On = onLeap
+	}
+	if err := c.checkBounds(input, p.Time); err != nil {
+		Logger().Info("input is outside the year bounds", "input", input, "err", err)
+		return nil, err
+	}
+	Logger().Info("input parsed", "input", input, "instant", p.Time)
+	return p, nil
+}
+
+// Convert parses the input and builds its result.
+func (c *Converter) Convert(input string) (*Result, error) {
+	p, err := c.Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return c.Result(p), nil
+}
+
+// Result builds the result of parsed input, keeping what was detected while parsing.
+func (c *Converter) Result(p *Parsed) *Result {
+	res := c.At(p.Time)
+	res.DetectedFormat = p.DetectedFormat
+	res.ID = p.ID
+	if p.LeapSecond != nil && p.LeapSecond.On && c.delta == "" {
+		res.LeapSecond = p.LeapSecond
+	}
+	return res
+}
+
+// At builds the result for the given time.
+func (c *Converter) At(tm time.Time) *Result {
+	warnings := c.Check(tm)
+
+	var loc *time.Location
+	if c.zone != "" {
+		var err error
+		if loc, err = LoadZone(c.zone); err != nil {
+			Logger().Warn("zone could not be loaded, it is not displayed", "zone", c.zone, "err", err)
+		} else {
+			Logger().Info("zone loaded", "zone", loc)
+		}
+	}
+	if c.delta != "" {
+		tm = c.addDelta(tm, loc)
+	}
+	if c.snapUnit != "" {
+		snapLoc := tm.Location()
+		if loc != nil {
+			snapLoc = loc
+		}
+		tm = Snap(tm.In(snapLoc), c.snapMode, c.snapUnit).In(tm.Location())
+		Logger().Info("time snapped", "mode", c.snapMode, "unit", c.snapUnit, "zone", snapLoc, "instant", tm)
+	}
+
+	layout := c.locale.DateLayout()
+	if c.format != "" {
+		var err error
+		if layout, err = TranslateLayout(c.format, c.dialect); err != nil {
+			Logger().Warn("format could not be translated, using the default format", "format", c.format, "dialect", c.dialect, "err", err)
+			layout = c.locale.DateLayout()
+		}
+	}
+	if resolved, fallback := outputLayout(layout); fallback && c.format != "" {
+		Logger().Warn("format has no layout elements, using the default format", "format", c.format, "layout", resolved)
+	} else {
+		Logger().Info("output layout", "format", c.format, "layout", resolved)
+	}
+	if c.locale != nil {
+		Logger().Info("output names in a locale", "locale", c.locale.Tag)
+	}
+
+	// the reading of a clock in the output scale, bases keep their own scales
+	reading := ToScale(tm, c.outputScale)
+	res := &Result{
+		Time:       tm,
+		Epoch:      c.Epoch(reading),
+		Precision:  c.precision,
+		Formatted:  FormatLocale(reading, layout, c.locale),
+		Local:      FormatLocale(reading.Local(), layout, c.locale),
+		UTC:        FormatLocale(reading.UTC(), layout, c.locale),
+		LeapSecond: NearLeapSecond(tm),
+		Warnings:   warnings,
+	}
+	if c.outputScale != ScaleUTC {
+		res.Scale = c.outputScale
+		Logger().Info("output read as a clock in a time scale", "scale", c.outputScale, "reading", reading)
+	}
+	if c.radix != 0 && c.radix != 10 {
+		res.Radix = c.radix
+		res.RadixValue = FormatInteger(res.Epoch, c.radix)
+	}
+	if c.outputBase != BaseUnix {
+		res.OutputBase = c.outputBase
+		res.BaseValue = ToEpochBase(tm, c.outputBase)
+	}
+
+	if loc != nil {
+		res.Zone = FormatLocale(reading.In(loc), layout, c.locale)
+		res.ZoneName = loc.String()
+		if offset, ok := ZoneOffset(c.zone); ok && c.offsetZones {
+			res.OffsetZones = ZonesAtOffset(offset, time.Now())
+			Logger().Info("zones at the fixed offset", "offset", formatOffset(offset), "zones", len(res.OffsetZones))
+		}
+	}
+	if c.epochs {
+		res.Epochs = Epochs(reading)
+		// bases keep their own scales
+		for _, base := range []EpochBase{c.base, c.outputBase} {
+			if base != BaseUnix && (len(res.EpochBases) == 0 || res.EpochBases[0].Base != base) {
+				res.EpochBases = append(res.EpochBases, BaseValue{Base: base, Value: ToEpochBase(tm, base)})
+			}
+		}
+	}
+	if c.bases {
+		res.Bases = AllBases(tm)
+	}
+	if c.metadata {
+		res.Metadata = &TimeMetadata{Unix: tm.Unix(), UnixMilli: tm.UnixMilli(),
+			Local: Describe(reading.Local()), UTC: Describe(reading.UTC())}
+		if loc != nil {
+			zone := Describe(reading.In(loc))
+			res.Metadata.Zone = &zone
+		}
+	}
+	if len(c.calendars) > 0 {
+		res.Calendars = c.calendarDates(reading, loc)
+	}
+	return res
+}
+
+// calendarDates is the date of tm in each calendar, in loc when set
+func (c *Converter) calendarDates(tm time.Time, loc *time.Location) []CalendarDate {
+	if loc != nil {
+		tm = tm.In(loc)
+	}
+	dates := make([]CalendarDate, 0, len(c.calendars))
+	for _, cal := range c.calendars {
+		date, err := ToCalendar(tm, cal)
+		if err != nil {
+			Logger().Warn("date could not be computed in the calendar", "calendar", cal, "instant", tm, "err", err)
+			date = CalendarDate{Calendar: cal, Error: err.Error()}
+		}
+		dates = append(dates, date)
+	}
+	Logger().Info("dates in calendars", "calendars", c.calendars, "zone", tm.Location())
+	return dates
+}
+
+// parseLayout is the layout of the format, when there is one with layout elements to parse input with
+func (c *Converter) parseLayout() (string, bool) {
+	if c.format == "" {
+		return "", false
+	}
+	layout, err := TranslateLayout(c.format, c.dialect)
+	if err != nil {
+		return "", false
+	}
+	layout, fallback := outputLayout(layout)
+	return layout, !fallback
+}
+
+// addDelta adds the delta to tm, business days are counted on the calendar of loc when set
+func (c *Converter) addDelta(tm time.Time, loc *time.Location) time.Time {
+	n, ok := ParseBusinessDelta(c.delta)
+	if !ok {
+		return AddDelta(tm, c.delta)
+	}
+	cal := c.business
+	if cal == nil {
+		cal = NewBusinessCalendar()
+	}
+	calLoc := tm.Location()
+	if loc != nil {
+		calLoc = loc
+	}
+	moved, err := cal.AddBusinessDays(tm.In(calLoc), n)
+	if err != nil {
+		Logger().Warn("business days could not be added, ignoring the delta", "delta", c.delta, "err", err)
+		return tm
+	}
+	Logger().Info("business days applied", "days", n, "weekend", cal.Weekend(), "zone", calLoc, "instant", moved)
+	return moved.In(tm.Location())
+}
@@ -0,0 +1,247 @@
+package dat
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tzLosAngeles = "America/Los_Angeles"
+)
+
+func TestConverter_Convert(t *testing.T) {
+	laZone, err := time.LoadLocation(tzLosAngeles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	epoch := time.Unix(1601167426, 0)
+	fr, err := LookupLocale("fr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	laMetadata := Describe(epoch.In(laZone))
+	leap2016 := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	startOfDay := time.Date(2020, 9, 26, 0, 0, 0, 0, laZone).Local()
+	// saturday in los angeles, the next business day is monday
+	nextBusinessDay := time.Date(2020, 9, 28, 17, 43, 46, 0, laZone).Local()
+
+	tests := []struct {
+		name  string
+		opts  []Option
+		input string
+		want  *Result
+		error bool
+	}{
+		{"epoch", nil, "1601167426", &Result{
+			Time:      epoch,
+			Epoch:     1601167426,
+			Formatted: epoch.Format(DateFormat),
+			Local:     epoch.Local().Format(DateFormat),
+			UTC:       epoch.UTC().Format(DateFormat),
+		}, false},
+		{"milliseconds with delta", []Option{WithPrecision(Milliseconds), WithDelta("1h")}, "1601167426000", &Result{
+			Time:      epoch.Add(time.Hour),
+			Epoch:     1601171026000,
+			Precision: Milliseconds,
+			Formatted: epoch.Add(time.Hour).Format(DateFormat),
+			Local:     epoch.Add(time.Hour).Local().Format(DateFormat),
+			UTC:       epoch.Add(time.Hour).UTC().Format(DateFormat),
+		}, false},
+		{"zone and format", []Option{WithZone(tzLosAngeles), WithFormat("rfc3339")}, "1601167426", &Result{
+			Time:      epoch,
+			Epoch:     1601167426,
+			Formatted: epoch.Format(time.RFC3339),
+			Local:     epoch.Local().Format(time.RFC3339),
+			UTC:       epoch.UTC().Format(time.RFC3339),
+			Zone:      epoch.In(laZone).Format(time.RFC3339),
+			ZoneName:  tzLosAngeles,
+		}, false},
+		{"fixed offset zone", []Option{WithZone("+05:30")}, "1601167426", &Result{
+			Time:      epoch,
+			Epoch:     1601167426,
+			Formatted: epoch.Format(DateFormat),
+			Local:     epoch.Local().Format(DateFormat),
+			UTC:       epoch.UTC().Format(DateFormat),
+			Zone:      epoch.In(time.FixedZone("UTC+05:30", 19800)).Format(DateFormat),
+			ZoneName:  "UTC+05:30",
+		}, false},
+		{"zones at a fixed offset", []Option{WithZone("+05:30"), WithOffsetZones(true)}, "1601167426", &Result{
+			Time:        epoch,
+			Epoch:       1601167426,
+			Formatted:   epoch.Format(DateFormat),
+			Local:       epoch.Local().Format(DateFormat),
+			UTC:         epoch.UTC().Format(DateFormat),
+			Zone:        epoch.In(time.FixedZone("UTC+05:30", 19800)).Format(DateFormat),
+			ZoneName:    "UTC+05:30",
+			OffsetZones: ZonesAtOffset(19800, time.Now()),
+		}, false},
+		{"time format", []Option{WithTimeFormats(true)}, epoch.UTC().Format(time.RFC1123), &Result{
+			Time:           epoch.UTC(),
+			Epoch:          1601167426,
+			Formatted:      epoch.UTC().Format(DateFormat),
+			Local:          epoch.Local().Format(DateFormat),
+			UTC:            epoch.UTC().Format(DateFormat),
+			DetectedFormat: "RFC1123",
+		}, false},
+		{"time format in a dialect", []Option{WithTimeFormats(true), WithDialect(DialectStrftime), WithFormat("%Y%m%d %H%M%S")},
+			epoch.UTC().Format("20060102 150405"), &Result{
+				Time:           epoch.UTC(),
+				Epoch:          1601167426,
+				Formatted:      epoch.UTC().Format("20060102 150405"),
+				Local:          epoch.Local().Format("20060102 150405"),
+				UTC:            epoch.UTC().Format("20060102 150405"),
+				DetectedFormat: "%Y%m%d %H%M%S",
+			}, false},
+		{"locale", []Option{WithTimeFormats(true), WithLocale(fr)}, "dimanche 27 septembre 2020 00:43:46", &Result{
+			Time:           epoch.UTC(),
+			Epoch:          1601167426,
+			Formatted:      epoch.UTC().Format("02/01/2006 15:04:05 -0700"),
+			Local:          epoch.Local().Format("02/01/2006 15:04:05 -0700"),
+			UTC:            epoch.UTC().Format("02/01/2006 15:04:05 -0700"),
+			DetectedFormat: "Monday 2 January 2006 15:04:05",
+		}, false},
+		{"calendars", []Option{WithZone(tzLosAngeles), WithCalendars(CalendarHebrew, CalendarJapanese)}, "1601167426", &Result{
+			Time:      epoch,
+			Epoch:     1601167426,
+			Formatted: epoch.Format(DateFormat),
+			Local:     epoch.Local().Format(DateFormat),
+			UTC:       epoch.UTC().Format(DateFormat),
+			Zone:      epoch.In(laZone).Format(DateFormat),
+			ZoneName:  tzLosAngeles,
+			Calendars: []CalendarDate{
+				{Calendar: CalendarHebrew, Era: "AM", Year: 5781, Month: 7, MonthName: "Tishrei", Day: 8, Text: "8 Tishrei 5781"},
+				{Calendar: CalendarJapanese, Era: "Reiwa", Year: 2, Month: 9, MonthName: "September", Day: 26,
+					Text: "Reiwa 2-09-26 (令和2年9月26日)"},
+			},
+		}, false},
+		{"output base", []Option{WithOutputBase(BaseMac)}, "1601167426", &Result{
+			Time:       epoch,
+			Epoch:      1601167426,
+			Formatted:  epoch.Format(DateFormat),
+			Local:      epoch.Local().Format(DateFormat),
+			UTC:        epoch.UTC().Format(DateFormat),
+			BaseValue:  "622860226",
+			OutputBase: BaseMac,
+		}, false},
+		{"epochs", []Option{WithEpochs(true), WithOutputScale(ScaleTAI)}, "1601167426", &Result{
+			Time:      epoch,
+			Scale:     ScaleTAI,
+			Epoch:     1601167463,
+			Formatted: epoch.Add(37 * time.Second).Format(DateFormat),
+			Local:     epoch.Add(37 * time.Second).Local().Format(DateFormat),
+			UTC:       epoch.Add(37 * time.Second).UTC().Format(DateFormat),
+			Epochs:    Epochs(epoch.Add(37 * time.Second)),
+		}, false},
+		{"epochs in bases", []Option{WithEpochs(true), WithEpochBase(BaseUnix), WithOutputBase(BaseMac)}, "1601167426", &Result{
+			Time:       epoch,
+			Epoch:      1601167426,
+			Formatted:  epoch.Format(DateFormat),
+			Local:      epoch.Local().Format(DateFormat),
+			UTC:        epoch.UTC().Format(DateFormat),
+			BaseValue:  "622860226",
+			OutputBase: BaseMac,
+			Epochs:     Epochs(epoch),
+			EpochBases: []BaseValue{{BaseMac, "622860226"}},
+		}, false},
+		{"bases", []Option{WithBases(true)}, "1601167426", &Result{
+			Time:      epoch,
+			Epoch:     1601167426,
+			Formatted: epoch.Format(DateFormat),
+			Local:     epoch.Local().Format(DateFormat),
+			UTC:       epoch.UTC().Format(DateFormat),
+			Bases:     AllBases(epoch),
+		}, false},
+		{"metadata", []Option{WithMetadata(true), WithZone(tzLosAngeles)}, "1601167426", &Result{
+			Time:      epoch,
+			Epoch:     1601167426,
+			Formatted: epoch.Format(DateFormat),
+			Local:     epoch.Local().Format(DateFormat),
+			UTC:       epoch.UTC().Format(DateFormat),
+			Zone:      epoch.In(laZone).Format(DateFormat),
+			ZoneName:  tzLosAngeles,
+			Metadata: &TimeMetadata{Unix: 1601167426, UnixMilli: 1601167426000,
+				Local: Describe(epoch.Local()), UTC: Describe(epoch.UTC()), Zone: &laMetadata},
+		}, false},
+		{"epoch base", []Option{WithEpochBase(BaseNTP)}, "3810156226", &Result{
+			Time:      epoch,
+			Epoch:     1601167426,
+			Formatted: epoch.Format(DateFormat),
+			Local:     epoch.Local().Format(DateFormat),
+			UTC:       epoch.UTC().Format(DateFormat),
+		}, false},
+		{"tai input", []Option{WithScale(ScaleTAI)}, "1601167463", &Result{
+			Time:      epoch,
+			Epoch:     1601167426,
+			Formatted: epoch.Format(DateFormat),
+			Local:     epoch.Local().Format(DateFormat),
+			UTC:       epoch.UTC().Format(DateFormat),
+		}, false},
+		{"gps output", []Option{WithOutputScale(ScaleGPS)}, "1601167426", &Result{
+			Time:      epoch,
+			Scale:     ScaleGPS,
+			Epoch:     1601167444,
+			Formatted: epoch.Add(18 * time.Second).Format(DateFormat),
+			Local:     epoch.Add(18 * time.Second).Local().Format(DateFormat),
+			UTC:       epoch.Add(18 * time.Second).UTC().Format(DateFormat),
+		}, false},
+		{"on leap second", []Option{WithScale(ScaleTAI)}, "1483228836", &Result{
+			Time:       leap2016.Add(-time.Second),
+			Epoch:      1483228799,
+			Formatted:  leap2016.Add(-time.Second).Format(DateFormat),
+			Local:      leap2016.Add(-time.Second).Local().Format(DateFormat),
+			UTC:        leap2016.Add(-time.Second).UTC().Format(DateFormat),
+			LeapSecond: &LeapSecondNote{Leap: leap2016, Offset: -time.Second, On: true},
+		}, false},
+		{"hex radix", []Option{WithRadix(16)}, "5f6fe042", &Result{
+			Time:       epoch,
+			Epoch:      1601167426,
+			Formatted:  epoch.Format(DateFormat),
+			Local:      epoch.Local().Format(DateFormat),
+			UTC:        epoch.UTC().Format(DateFormat),
+			RadixValue: "0x5f6fe042",
+			Radix:      16,
+		}, false},
+		{"little endian bytes", []Option{WithBytes(binary.LittleEndian)}, "42e06f5f", &Result{
+			Time:      epoch,
+			Epoch:     1601167426,
+			Formatted: epoch.Format(DateFormat),
+			Local:     epoch.Local().Format(DateFormat),
+			UTC:       epoch.UTC().Format(DateFormat),
+		}, false},
+		{"start of day in a zone", []Option{WithZone(tzLosAngeles), WithSnap(SnapTruncate, UnitDay)}, "1601167426", &Result{
+			Time:      startOfDay,
+			Epoch:     startOfDay.Unix(),
+			Formatted: startOfDay.Format(DateFormat),
+			Local:     startOfDay.Local().Format(DateFormat),
+			UTC:       startOfDay.UTC().Format(DateFormat),
+			Zone:      "09/26/2020 00:00:00 -0700",
+			ZoneName:  tzLosAngeles,
+		}, false},
+		{"business days in a zone", []Option{WithZone(tzLosAngeles), WithDelta("+1bd"), WithBusinessCalendar(NewBusinessCalendar())}, "1601167426", &Result{
+			Time:      nextBusinessDay,
+			Epoch:     nextBusinessDay.Unix(),
+			Formatted: nextBusinessDay.Format(DateFormat),
+			Local:     nextBusinessDay.Local().Format(DateFormat),
+			UTC:       nextBusinessDay.UTC().Format(DateFormat),
+			Zone:      "09/28/2020 17:43:46 -0700",
+			ZoneName:  tzLosAngeles,
+		}, false},
+		{"bad epoch", nil, "asdf", nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NewConverter(test.opts...).Convert(test.input)
+			if test.error {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, test.want.Time.Equal(got.Time))
+			got.Time = test.want.Time
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package dat
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	// Expr is the expression the schedule was parsed from
+	Expr string
+	// Location is the zone of CRON_TZ, nil to use the zone of the times it is given
+	Location *time.Location
+
+	second, minute, hour, dom, month, dow uint64
+	// domStar and dowStar are set when the field was * or ?, see Schedule.dayMatches
+	domStar, dowStar bool
+}
+
+// cronField is the range and names of a cron field
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	secondField = cronField{name: "second", min: 0, max: 59}
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is also sunday
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// cronMacros are the @ shorthands for common schedules
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSearchYears is how far Next and Prev look for a fire time, enough for
+// the rarest schedules such as february 29th on a monday
+const cronSearchYears = 30
+
+// ParseCron parses a cron expression of 5 fields (minute hour day-of-month month day-of-week),
+// 6 fields with a leading second, or an @ macro such as @daily. A CRON_TZ=Zone or TZ=Zone
+// prefix evaluates the schedule in that zone, see LoadZone.
+func ParseCron(expr string) (*Schedule, error) {
+	s := &Schedule{Expr: expr}
+	spec := strings.TrimSpace(expr)
+	for _, prefix := range []string{"CRON_TZ=", "TZ="} {
+		if !strings.HasPrefix(spec, prefix) {
+			continue
+		}
+		fields := strings.SplitN(spec[len(prefix):], " ", 2)
+		loc, err := LoadZone(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron zone %q: %w", fields[0], err)
+		}
+		s.Location = loc
+		spec = ""
+		if len(fields) > 1 {
+			spec = strings.TrimSpace(fields[1])
+		}
+		break
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		macro, ok := cronMacros[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("unknown cron macro %q", spec)
+		}
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("invalid cron expression %q, expected 5 or 6 fields, got %d", expr, len(fields))
+	}
+
+	var err error
+	for i, f := range []struct {
+		bits  *uint64
+		star  *bool
+		field cronField
+	}{
+		{&s.second, nil, secondField},
+		{&s.minute, nil, minuteField},
+		{&s.hour, nil, hourField},
+		{&s.dom, &s.domStar, domField},
+		{&s.month, nil, monthField},
+		{&s.dow, &s.dowStar, dowField},
+	} {
+		if *f.bits, err = f.field.parse(fields[i]); err != nil {
+			return nil, err
+		}
+		if f.star != nil {
+			*f.star = fields[i] == "*" || fields[i] == "?" || strings.HasPrefix(fields[i], "*/")
+		}
+	}
+	// sunday is both 0 and 7
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parse reads a comma separated list of values, ranges and steps into a bit set
+func (f cronField) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid %s step %q", f.name, part)
+			}
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*" || rng == "?":
+		case strings.Contains(rng, "-"):
+			from, to, _ := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(from); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(to); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid %s range %q", f.name, rng)
+			}
+		default:
+			v, err := f.value(rng)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if !hasStep {
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value reads a single number or name of the field
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q, must be %d-%d", f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// dayMatches reports whether the schedule fires on the day of tm. Like cron, when both
+// day fields are restricted either may match, otherwise both must.
+func (s *Schedule) dayMatches(tm time.Time) bool {
+	dom := s.dom&(1<<uint(tm.Day())) != 0
+	dow := s.dow&(1<<uint(tm.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// ErrNoFireTime the schedule does not fire within the searched years
+var ErrNoFireTime = errors.New("schedule does not fire")
+
+// Next returns the first fire time after tm, in the schedule's location or tm's.
+func (s *Schedule) Next(tm time.Time) (time.Time, error) {
+	if s.Location != nil {
+		tm = tm.In(s.Location)
+	}
+	loc := tm.Location()
+	// the start of the next second
+	t := tm.Add(time.Second - time.Duration(tm.Nanosecond()))
+	limit := t.Year() + cronSearchYears
+
+wrap:
+	for t.Year() <= limit {
+		for s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			if t.Month() == time.January {
+				continue wrap
+			}
+		}
+		for !s.dayMatches(t) {
+			month := t.Month()
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			if t.Month() != month {
+				continue wrap
+			}
+		}
+		for s.hour&(1<<uint(t.Hour())) == 0 {
+			day := t.Day()
+			t = StartOf(t, UnitHour).Add(time.Hour)
+			if t.Day() != day {
+				continue wrap
+			}
+		}
+		for s.minute&(1<<uint(t.Minute())) == 0 {
+			hour := t.Hour()
+			t = StartOf(t, UnitMinute).Add(time.Minute)
+			if t.Hour() != hour {
+				continue wrap
+			}
+		}
+		for s.second&(1<<uint(t.Second())) == 0 {
+			minute := t.Minute()
+			t = t.Add(time.Second)
+			if t.Minute() != minute {
+				continue wrap
+			}
+		}
+		if repeatedWall(t) {
+			// the wall clock already fired before clocks fell back
+			t = t.Add(time.Second)
+			continue
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q %w within %d years of %s", s.Expr, ErrNoFireTime, cronSearchYears, tm.Format(time.RFC3339))
+}
+
+// repeatedWall reports whether the wall clock of tm already occurred, in the hour that repeats
+// after clocks fall back. Cron fires once per wall clock time, at its first occurrence.
+func repeatedWall(tm time.Time) bool {
+	start, _ := tm.ZoneBounds()
+	if start.IsZero() {
+		return false
+	}
+	_, offset := tm.Zone()
+	_, before := start.Add(-time.Second).Zone()
+	return before > offset && tm.Before(start.Add(time.Duration(before-offset)*time.Second))
+}
+
+// Prev returns the last fire time before tm, in the schedule's location or tm's.
+func (s *Schedule) Prev(tm time.Time) (time.Time, error) {
+	if s.Location != nil {
+		tm = tm.In(s.Location)
+	}
+	loc := tm.Location()
+	// the start of the previous second
+	t := tm.Add(-time.Nanosecond)
+	t = t.Add(-time.Duration(t.Nanosecond()))
+	limit := t.Year() - cronSearchYears
+
+wrap:
+	for t.Year() >= limit {
+		for s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc).Add(-time.Second)
+			if t.Month() == time.December {
+				continue wrap
+			}
+		}
+		for !s.dayMatches(t) {
+			month := t.Month()
+			t = StartOf(t, UnitDay).Add(-time.Second)
+			if t.Month() != month {
+				continue wrap
+			}
+		}
+		for s.hour&(1<<uint(t.Hour())) == 0 {
+			day := t.Day()
+			t = StartOf(t, UnitHour).Add(-time.Second)
+			if t.Day() != day {
+				continue wrap
+			}
+		}
+		for s.minute&(1<<uint(t.Minute())) == 0 {
+			hour := t.Hour()
+			t = StartOf(t, UnitMinute).Add(-time.Second)
+			if t.Hour() != hour {
+				continue wrap
+			}
+		}
+		for s.second&(1<<uint(t.Second())) == 0 {
+			minute := t.Minute()
+			t = t.Add(-time.Second)
+			if t.Minute() != minute {
+				continue wrap
+			}
+		}
+		if repeatedWall(t) {
+			// the wall clock fires before clocks fall back
+			t = t.Add(-time.Second)
+			continue
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q %w within %d years of %s", s.Expr, ErrNoFireTime, cronSearchYears, tm.Format(time.RFC3339))
+}
@@ -0,0 +1,173 @@
+package dat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{"* * * * *", ""},
+		{"0 */15 9-17 * * mon-fri", ""},
+		{"0 0 1,15 jan-jun ?", ""},
+		{"@Daily", ""},
+		{"CRON_TZ=Asia/Tokyo 30 9 * * *", ""},
+		{"TZ=UTC @hourly", ""},
+		{"* * * *", `invalid cron expression "* * * *", expected 5 or 6 fields, got 4`},
+		{"60 * * * *", `invalid minute "60", must be 0-59`},
+		{"* * * foo *", `invalid month "foo", must be 1-12`},
+		{"* * * * fri-mon", `invalid day of week range "fri-mon"`},
+		{"*/0 * * * *", `invalid minute step "*/0"`},
+		{"@reboot", `unknown cron macro "@reboot"`},
+	}
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			_, err := ParseCron(test.expr)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	_, err := ParseCron("CRON_TZ=Mars/Olympus @daily")
+	assert.Error(t, err)
+}
+
+func TestSchedule_Next(t *testing.T) {
+	// a thursday
+	from := time.Date(2024, 5, 16, 14, 37, 42, 500, time.UTC)
+	tests := []struct {
+		expr       string
+		next, prev time.Time
+	}{
+		{"* * * * *",
+			time.Date(2024, 5, 16, 14, 38, 0, 0, time.UTC),
+			time.Date(2024, 5, 16, 14, 37, 0, 0, time.UTC)},
+		{"*/10 * * * * *",
+			time.Date(2024, 5, 16, 14, 37, 50, 0, time.UTC),
+			time.Date(2024, 5, 16, 14, 37, 40, 0, time.UTC)},
+		{"@daily",
+			time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 16, 0, 0, 0, 0, time.UTC)},
+		{"@weekly",
+			time.Date(2024, 5, 19, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 12, 0, 0, 0, 0, time.UTC)},
+		{"@yearly",
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 9 * * mon-fri",
+			time.Date(2024, 5, 17, 9, 30, 0, 0, time.UTC),
+			time.Date(2024, 5, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 12 * * 7",
+			time.Date(2024, 5, 19, 12, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 12, 12, 0, 0, 0, time.UTC)},
+		// either day field matches when both are restricted
+		{"0 0 1 * fri",
+			time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * *",
+			time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *",
+			time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"CRON_TZ=Asia/Tokyo 0 9 * * *",
+			time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 16, 0, 0, 0, 0, time.UTC)},
+		{"CRON_TZ=+09:00 0 9 * * *",
+			time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 16, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			s, err := ParseCron(test.expr)
+			if !assert.NoError(t, err) {
+				return
+			}
+			next, err := s.Next(from)
+			assert.NoError(t, err)
+			assert.True(t, test.next.Equal(next), "next %s", next)
+			prev, err := s.Prev(from)
+			assert.NoError(t, err)
+			assert.True(t, test.prev.Equal(prev), "prev %s", prev)
+		})
+	}
+
+	s, err := ParseCron("0 0 30 feb *")
+	assert.NoError(t, err)
+	_, err = s.Next(from)
+	assert.ErrorIs(t, err, ErrNoFireTime)
+	_, err = s.Prev(from)
+	assert.ErrorIs(t, err, ErrNoFireTime)
+}
+
+func TestSchedule_Zones(t *testing.T) {
+	la, err := time.LoadLocation(tzLosAngeles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := ParseCron("30 2 * * *")
+	assert.NoError(t, err)
+
+	// 02:30 does not exist on the day daylight saving starts
+	next, err := s.Next(time.Date(2024, 3, 9, 12, 0, 0, 0, la))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 11, 2, 30, 0, 0, la), next)
+
+	// fire times follow the wall clock across the change
+	hourly, err := ParseCron("0 * * * *")
+	assert.NoError(t, err)
+	next, err = hourly.Next(time.Date(2024, 3, 10, 1, 30, 0, 0, la))
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-03-10T03:00:00-07:00", next.Format(time.RFC3339))
+	prev, err := hourly.Prev(next)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-03-10T01:00:00-08:00", prev.Format(time.RFC3339))
+}
+
+func TestSchedule_FallBack(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := ParseCron("0 1 * * *")
+	assert.NoError(t, err)
+
+	// 01:00 happens twice on 2023-11-05 in new york, cron fires at the first one only
+	var got []string
+	tm := time.Date(2023, 11, 5, 0, 53, 0, 0, ny)
+	for i := 0; i < 3; i++ {
+		tm, err = s.Next(tm)
+		assert.NoError(t, err)
+		got = append(got, tm.Format(time.RFC3339))
+	}
+	assert.Equal(t, []string{"2023-11-05T01:00:00-04:00", "2023-11-06T01:00:00-05:00", "2023-11-07T01:00:00-05:00"}, got)
+
+	got = nil
+	tm = time.Date(2023, 11, 6, 0, 0, 0, 0, ny)
+	for i := 0; i < 2; i++ {
+		tm, err = s.Prev(tm)
+		assert.NoError(t, err)
+		got = append(got, tm.Format(time.RFC3339))
+	}
+	assert.Equal(t, []string{"2023-11-05T01:00:00-04:00", "2023-11-04T01:00:00-04:00"}, got)
+
+	// from within the repeated hour, the first 01:30 is behind and the second is skipped
+	half, err := ParseCron("30 1 * * *")
+	assert.NoError(t, err)
+	repeated := time.Date(2023, 11, 5, 5, 45, 0, 0, time.UTC).In(ny)
+	assert.Equal(t, "2023-11-05T01:45:00-04:00", repeated.Format(time.RFC3339))
+	next, err := half.Next(repeated)
+	assert.NoError(t, err)
+	assert.Equal(t, "2023-11-06T01:30:00-05:00", next.Format(time.RFC3339))
+	prev, err := half.Prev(repeated.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "2023-11-05T01:30:00-04:00", prev.Format(time.RFC3339))
+}
@@ -0,0 +1,183 @@
+// Package dat converts between epochs and formatted times.
+// It is the engine behind the dat command line tool and can be
+// embedded anywhere the same parsing and formatting is needed.
+package dat
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateFormat is the default output layout.
+const DateFormat = "01/02/2006 15:04:05 -0700"
+
+type namedFormat struct {
+	name   string
+	layout string
+}
+
+// supportedTimeFormats are the named layouts, in the order they are tried when parsing.
+var supportedTimeFormats = []namedFormat{
+	{"ANSIC", time.ANSIC},
+	{"UnixDate", time.UnixDate},
+	{"RubyDate", time.RubyDate},
+	{"RFC822", time.RFC822},
+	{"RFC822Z", time.RFC822Z},
+	{"RFC850", time.RFC850},
+	{"RFC1123", time.RFC1123},
+	{"RFC1123Z", time.RFC1123Z},
+	{"RFC3339", time.RFC3339},
+	{"RFC3339Nano", time.RFC3339Nano},
+	{"Kitchen", time.Kitchen},
+	{"Stamp", time.Stamp},
+	{"StampMilli", time.StampMilli},
+	{"StampMicro", time.StampMicro},
+	{"StampNano", time.StampNano},
+}
+
+// FormatNames returns the names of the supported time formats.
+func FormatNames() []string {
+	names := make([]string, 0, len(supportedTimeFormats))
+	for _, f := range supportedTimeFormats {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+// LookupFormat returns the layout for the given format name, case insensitive.
+func LookupFormat(name string) (string, bool) {
+	for _, f := range supportedTimeFormats {
+		if strings.EqualFold(f.name, name) {
+			return f.layout, true
+		}
+	}
+	return "", false
+}
+
+// FormatName returns the name of the given layout if it is a supported time format.
+func FormatName(layout string) (string, bool) {
+	for _, f := range supportedTimeFormats {
+		if f.layout == layout {
+			return f.name, true
+		}
+	}
+	return "", false
+}
+
+// AddDelta simply adds the given duration if it is valid to the given time, ignores otherwise.
+func AddDelta(tm time.Time, delta string) time.Time {
+	dur, err := time.ParseDuration(delta)
+	if err != nil {
+		Logger().Warn("delta is not a duration, ignoring it", "delta", delta, "err", err)
+		return tm
+	}
+	Logger().Info("delta applied", "delta", dur, "instant", tm.Add(dur))
+	return tm.Add(dur)
+}
+
+// FormatOutput parses the provided time against the provided format string.
+// replacing named constants with the expected format.
+func FormatOutput(tm time.Time, outFmtS string) string {
+	layout, _ := outputLayout(outFmtS)
+	return tm.Format(layout)
+}
+
+// ParseEpochTime tries to parse the string as an int, then converts to a time.Time
+func ParseEpochTime(str string, milliseconds bool) (time.Time, error) {
+	return ParseEpochRadix(str, 0, milliseconds)
+}
+
+// ParseTime tries each of the supported time formats,
+// returning the time and the layout of the first that matched.
+// Input matching several formats that disagree on the time is ambiguous.
+func ParseTime(str string) (time.Time, string, error) {
+	tm, f, err := parseTime(str, nil)
+	return tm, f.layout, err
+}
+
+// ParseTimeLocale is ParseTime reading the month and weekday names of the locale, that
+// also tries the date orderings of the locale after the supported time formats.
+func ParseTimeLocale(str string, l *Locale) (time.Time, string, error) {
+	tm, f, err := parseTime(str, l)
+	return tm, f.layout, err
+}
+
+// parseTime tries the supported time formats and those of the locale, returning the first that matched
+func parseTime(str string, l *Locale) (time.Time, namedFormat, error) {
+	var (
+		tm      time.Time
+		matched namedFormat
+		matches []string
+		// closest tracks the format that parsed the furthest into str
+		closest    namedFormat
+		closestPos = -1
+	)
+	formats := append(append([]namedFormat{}, supportedTimeFormats...), l.formats()...)
+	for _, f := range formats {
+		got, err := parseLocale(f.layout, str, l)
+		if err != nil {
+			Logger().Debug("layout did not match", "format", f.name, "layout", f.layout, "err", err)
+			var terr *time.ParseError
+			if errors.As(err, &terr) {
+				if pos := len(terr.Value) - len(terr.ValueElem); pos > closestPos {
+					closest, closestPos = f, pos
+				}
+			}
+			continue
+		}
+		Logger().Debug("layout matched", "format", f.name, "layout", f.layout, "instant", got)
+		if matched.layout == "" {
+			tm, matched = got, f
+			matches = append(matches, f.name)
+		} else if !got.Equal(tm) {
+			matches = append(matches, f.name)
+		}
+	}
+
+	switch {
+	case len(matches) > 1:
+		Logger().Info("input matches formats that disagree", "input", str, "formats", matches)
+		pe := &ParseError{Input: str, Pos: -1, Err: ErrAmbiguous}
+		for _, name := range matches {
+			pe.Suggestions = append(pe.Suggestions, fmt.Sprintf("matches %s, use an explicit format", name))
+		}
+		return time.Unix(0, 0), namedFormat{}, pe
+	case matched.layout != "":
+		Logger().Info("input matched a time format", "input", str, "format", matched.name, "layout", matched.layout)
+		return tm, matched, nil
+	}
+	Logger().Info("input matched no time format", "input", str, "closest", closest.name)
+
+	pe := &ParseError{Input: str, Pos: closestPos, Err: ErrUnknownFormat}
+	if _, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64); err == nil {
+		pe.Suggestions = append(pe.Suggestions, "input looks like an epoch, parse it as an epoch")
+	}
+	switch {
+	case closest.name == closest.layout && closest.name != "":
+		pe.Suggestions = append(pe.Suggestions, fmt.Sprintf("closest format is %q", closest.layout))
+	case closest.name != "":
+		pe.Suggestions = append(pe.Suggestions, fmt.Sprintf("closest format is %s %q", closest.name, closest.layout))
+	}
+	return time.Unix(0, 0), namedFormat{}, pe
+}
+
+// TruncateString reduces the size of str to the given size.
+// if str is truncated, it will include a trailing ellipsis.
+func TruncateString(str string, size int) string {
+	if size < 0 {
+		size = 0
+	}
+	length := len(str)
+	postfix := ""
+	if length > size {
+		if length > 3 {
+			postfix = "..."
+			size -= 3
+		}
+		return str[0:size] + postfix
+	}
+	return str
+}
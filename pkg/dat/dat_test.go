@@ -0,0 +1,199 @@
+package dat
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEpochTime(t *testing.T) {
+	timeEpoch := int64(1572762509)
+	timeEpochMillis := int64(1572762509000)
+	tmStr := strconv.FormatInt(timeEpoch, 10)
+	tmStrMillis := strconv.FormatInt(timeEpochMillis, 10)
+	tests := []struct {
+		name           string
+		str            string
+		isMilliseconds bool
+		want           time.Time
+		error          bool
+	}{
+		{"can't parse", "qqqqqq", false, time.Time{}, true},
+		{"parsed", tmStr, false, time.Unix(timeEpoch, 0), false},
+		{"parsed", tmStrMillis, true, time.Unix(0, timeEpochMillis*int64(time.Millisecond)), false},
+		{"hex", "0x5DBE738D", false, time.Unix(timeEpoch, 0), false},
+		{"binary milliseconds", "0b" + strconv.FormatInt(timeEpochMillis, 2), true, time.UnixMilli(timeEpochMillis), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseEpochTime(test.str, test.isMilliseconds)
+			if test.error {
+				assert.ErrorIs(t, err, ErrNotAnEpoch)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, test.want.Equal(got), "want:%d got:%d", test.want.UnixNano(), got.UnixNano())
+			}
+		})
+	}
+}
+
+func TestTruncateString(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		want string
+	}{
+		{"", 0, ""},
+		{"", -1, ""},
+		{"no trunc", 10, "no trunc"},
+		{"ab", 1, "a"},
+		{"abc", 2, "ab"},
+		{"abc", 3, "abc"},
+		{"abcd", 3, "..."},
+		{"happy trees", 8, "happy..."},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := TruncateString(test.name, test.size)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestFormatOutput(t *testing.T) {
+	testTime := time.Now()
+
+	tests := []struct {
+		name   string
+		time   time.Time
+		format string
+		want   string
+	}{
+		{"ANSIC", testTime, "ansic", testTime.Format(time.ANSIC)},
+		{"UnixDate", testTime, "UnixDate", testTime.Format(time.UnixDate)},
+		{"RubyDate", testTime, "RubyDate", testTime.Format(time.RubyDate)},
+		{"RFC822", testTime, "RFC822", testTime.Format(time.RFC822)},
+		{"RFC822Z", testTime, "RFC822Z", testTime.Format(time.RFC822Z)},
+		{"RFC850", testTime, "RFC850", testTime.Format(time.RFC850)},
+		{"RFC1123", testTime, "RFC1123", testTime.Format(time.RFC1123)},
+		{"RFC1123Z", testTime, "RFC1123Z", testTime.Format(time.RFC1123Z)},
+		{"RFC3339", testTime, "RFC3339", testTime.Format(time.RFC3339)},
+		{"RFC3339Nano", testTime, "RFC3339Nano", testTime.Format(time.RFC3339Nano)},
+		{"Kitchen", testTime, "Kitchen", testTime.Format(time.Kitchen)},
+		{"Stamp", testTime, "Stamp", testTime.Format(time.Stamp)},
+		{"StampMilli", testTime, "StampMilli", testTime.Format(time.StampMilli)},
+		{"StampMicro", testTime, "StampMicro", testTime.Format(time.StampMicro)},
+		{"StampNano", testTime, "StampNano", testTime.Format(time.StampNano)},
+		{"other format", testTime, "Jan 15:05:04 MST -700 2006", testTime.Format("Jan 15:05:04 MST -700 2006")},
+		{"unknown format", testTime, "NoTaGoOdFoRmAt", testTime.Format(DateFormat)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := FormatOutput(test.time, test.format)
+			assert.Equal(t, got, test.want)
+		})
+	}
+}
+
+func TestAddDelta(t *testing.T) {
+	tests := []struct {
+		name         string
+		time         time.Time
+		delta        string
+		expectedTime time.Time
+	}{
+		{"no delta, no addition", time.Unix(1625211007, 0), "", time.Unix(1625211007, 0)},
+		{"adding 300h2m", time.Unix(1625211007, 0), "300h2m", time.Unix(1626291127, 0)},
+		{"subtracting 300h2m", time.Unix(1625211007, 0), "-300h2m", time.Unix(1624130887, 0)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := AddDelta(test.time, test.delta)
+			assert.Equal(t, test.expectedTime, got)
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	testTime := time.Date(2020, 9, 27, 0, 43, 46, 0, time.UTC)
+	tests := []struct {
+		name   string
+		str    string
+		layout string
+		error  bool
+	}{
+		{"rfc3339", testTime.Format(time.RFC3339), time.RFC3339, false},
+		{"unix date", testTime.Format(time.UnixDate), time.UnixDate, false},
+		{"unknown", "qqqqqq", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, layout, err := ParseTime(test.str)
+			if test.error {
+				assert.ErrorIs(t, err, ErrUnknownFormat)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.layout, layout)
+				assert.True(t, testTime.Equal(got))
+			}
+		})
+	}
+}
+
+func TestLookupFormat(t *testing.T) {
+	layout, ok := LookupFormat("rfc3339")
+	assert.True(t, ok)
+	assert.Equal(t, time.RFC3339, layout)
+
+	name, ok := FormatName(time.Kitchen)
+	assert.True(t, ok)
+	assert.Equal(t, "Kitchen", name)
+
+	_, ok = LookupFormat("nope")
+	assert.False(t, ok)
+	assert.Contains(t, FormatNames(), "StampNano")
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		err         error
+		pos         int
+		suggestions int
+	}{
+		{"garbage", "12ab", ErrNotAnEpoch, 2, 0},
+		{"leading space", "  1x", ErrNotAnEpoch, 3, 0},
+		{"empty", "", ErrNotAnEpoch, -1, 1},
+		{"fraction", "1601167426.5", ErrNotAnEpoch, 10, 1},
+		{"time format", "2020-09-27T00:43:46Z", ErrNotAnEpoch, 4, 1},
+		{"overflow", "99999999999999999999", ErrOutOfRange, -1, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseEpochTime(test.input, false)
+			var pe *ParseError
+			if assert.ErrorAs(t, err, &pe) {
+				assert.ErrorIs(t, err, test.err)
+				assert.Equal(t, test.input, pe.Input)
+				assert.Equal(t, test.pos, pe.Pos)
+				assert.Len(t, pe.Suggestions, test.suggestions)
+			}
+		})
+	}
+
+	_, _, err := ParseTime("2020-09-27T00:43:4")
+	var pe *ParseError
+	if assert.ErrorAs(t, err, &pe) {
+		assert.ErrorIs(t, err, ErrUnknownFormat)
+		assert.Equal(t, 17, pe.Pos)
+		assert.Contains(t, pe.Suggestions[0], "RFC3339")
+	}
+
+	// the deprecated names still match
+	assert.ErrorIs(t, err, ErrInvalidTimeFormat)
+	_, err = ParseEpochTime("12ab", false)
+	assert.ErrorIs(t, err, ErrInvalidEpoch)
+}
@@ -0,0 +1,331 @@
+package dat
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect is a syntax formats are written in
+type Dialect string
+
+const (
+	// DialectGo go layouts written with the reference time, such as 2006-01-02
+	DialectGo Dialect = "go"
+	// DialectStrftime C, python and ruby strftime conversions, such as %Y-%m-%d
+	DialectStrftime Dialect = "strftime"
+	// DialectJava Java DateTimeFormatter and ICU patterns, such as yyyy-MM-dd
+	DialectJava Dialect = "java"
+	// DialectMoment moment.js and day.js tokens, such as YYYY-MM-DD
+	DialectMoment Dialect = "moment"
+)
+
+// Dialects are the supported format dialects
+var Dialects = []Dialect{DialectGo, DialectStrftime, DialectJava, DialectMoment}
+
+// dialectAliases are alternate names for dialects
+var dialectAliases = map[string]Dialect{
+	"c":      DialectStrftime,
+	"python": DialectStrftime,
+	"icu":    DialectJava,
+	"kotlin": DialectJava,
+	"dayjs":  DialectMoment,
+}
+
+// ParseDialect returns the dialect with the given name or alias, case insensitive.
+func ParseDialect(name string) (Dialect, error) {
+	lower := strings.ToLower(strings.TrimSpace(name))
+	if lower == "" {
+		return DialectGo, nil
+	}
+	if d, ok := dialectAliases[lower]; ok {
+		return d, nil
+	}
+	for _, d := range Dialects {
+		if Dialect(lower) == d {
+			return d, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format dialect %q, expected go, strftime, java or moment", name)
+}
+
+// TranslateLayout translates a format written in the dialect to a layout. The names of the
+// supported formats, such as RFC3339, are accepted in every dialect. Elements of the format
+// that have no layout element fail with ErrInvalidLayout, suggesting what failed.
+func TranslateLayout(format string, dialect Dialect) (string, error) {
+	if layout, ok := LookupFormat(format); ok {
+		return layout, nil
+	}
+	var elements []layoutElement
+	var err error
+	switch dialect {
+	case DialectGo, "":
+		return format, nil
+	case DialectStrftime:
+		elements, err = strftimeElements(format)
+	case DialectJava:
+		elements, err = javaElements(format)
+	case DialectMoment:
+		elements, err = momentElements(format)
+	default:
+		return "", fmt.Errorf("unknown format dialect %q", dialect)
+	}
+	layout := ""
+	if err == nil {
+		layout, err = compileLayout(elements)
+	}
+	if err != nil {
+		return "", &ParseError{Input: format, Pos: -1, Err: ErrInvalidLayout,
+			Suggestions: []string{fmt.Sprintf("%s pattern: %s", dialect, err)}}
+	}
+	return layout, nil
+}
+
+// TranslateJava translates a Java DateTimeFormatter or ICU pattern such as yyyy-MM-dd'T'HH:mm:ss.SSSXXX
+// to a layout. Text in quotes is literal, and letters without a layout element are an error.
+func TranslateJava(pattern string) (string, error) {
+	elements, err := javaElements(pattern)
+	if err != nil {
+		return "", err
+	}
+	return compileLayout(elements)
+}
+
+// layoutElement is a piece of a translated format: an element of the reference time,
+// literal text or a fraction of a second
+type layoutElement struct {
+	layout  string
+	literal bool
+	// fraction is the number of digits of a fraction of a second, written as source
+	fraction int
+	source   string
+}
+
+// element is a layout element of the reference time
+func element(layout string) layoutElement {
+	return layoutElement{layout: layout}
+}
+
+// literal is literal text
+func literal(text string) layoutElement {
+	return layoutElement{layout: text, literal: true}
+}
+
+// fraction is a fraction of a second with the given digits, written as source
+func fraction(digits int, source string) layoutElement {
+	return layoutElement{fraction: digits, source: source}
+}
+
+// compileLayout joins the elements into a layout. Literal text that a layout would read as an
+// element and fractions that do not follow a . or , separator cannot be written as a layout.
+func compileLayout(elements []layoutElement) (string, error) {
+	var b strings.Builder
+	for _, e := range elements {
+		switch {
+		case e.fraction > 0:
+			prev := b.String()
+			if !strings.HasSuffix(prev, ".") && !strings.HasSuffix(prev, ",") {
+				return "", fmt.Errorf("fraction %q is only supported after a . or , separator", e.source)
+			}
+			b.WriteString(strings.Repeat("0", e.fraction))
+		case e.literal && hasLayoutElements(e.layout):
+			return "", fmt.Errorf("literal %q would be read as a layout element", e.layout)
+		default:
+			b.WriteString(e.layout)
+		}
+	}
+	return b.String(), nil
+}
+
+// javaLayouts are the layout elements of java pattern letters by the number of times they repeat,
+// the last applies to longer runs
+var javaLayouts = map[rune][]string{
+	'y': {"2006", "06", "2006"},
+	'u': {"2006", "06", "2006"},
+	'M': {"1", "01", "Jan", "January"},
+	'L': {"1", "01", "Jan", "January"},
+	'd': {"2", "02"},
+	'D': {"", "", "002"},
+	'E': {"Mon", "Mon", "Mon", "Monday"},
+	'a': {"PM"},
+	'H': {"", "15"},
+	'h': {"3", "03"},
+	'm': {"4", "04"},
+	's': {"5", "05"},
+	'X': {"Z07", "Z0700", "Z07:00", "Z070000", "Z07:00:00"},
+	'x': {"-07", "-0700", "-07:00", "-070000", "-07:00:00"},
+	'Z': {"-0700", "-0700", "-0700", "", "Z07:00"},
+	'z': {"MST", "MST", "MST"},
+}
+
+// javaElements reads a java pattern, letters repeat to choose the element
+func javaElements(pattern string) ([]layoutElement, error) {
+	var elements []layoutElement
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		n := 1
+		for i+n < len(runes) && runes[i+n] == r {
+			n++
+		}
+
+		switch {
+		case r == '\'':
+			if n >= 2 {
+				elements = append(elements, literal(strings.Repeat("'", n/2)))
+				i += n / 2 * 2
+				continue
+			}
+			// quoted text is literal, a doubled quote inside it is a quote
+			var text strings.Builder
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == '\'' {
+					if j+1 < len(runes) && runes[j+1] == '\'' {
+						text.WriteRune('\'')
+						j++
+						continue
+					}
+					break
+				}
+				text.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("unterminated quote in %q", pattern)
+			}
+			elements = append(elements, literal(text.String()))
+			i = j + 1
+			continue
+		case r == 'S':
+			elements = append(elements, fraction(n, strings.Repeat("S", n)))
+		case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+			layouts, ok := javaLayouts[r]
+			if !ok {
+				return nil, fmt.Errorf("pattern letter %q has no layout element", r)
+			}
+			layout := layouts[len(layouts)-1]
+			if n <= len(layouts) {
+				layout = layouts[n-1]
+			}
+			if layout == "" {
+				return nil, fmt.Errorf("pattern %q has no layout element", strings.Repeat(string(r), n))
+			}
+			elements = append(elements, element(layout))
+		default:
+			elements = append(elements, literal(strings.Repeat(string(r), n)))
+		}
+		i += n
+	}
+	return elements, nil
+}
+
+// momentTokens are the moment.js tokens, longest first so the longest token matches.
+// Tokens without a layout element are listed so they fail by name.
+var momentTokens = []struct {
+	token  string
+	layout string
+}{
+	{"YYYYYY", ""}, {"MMMM", "January"}, {"DDDD", "002"}, {"dddd", "Monday"}, {"YYYY", "2006"},
+	{"gggg", ""}, {"GGGG", ""}, {"NNNN", ""},
+	{"MMM", "Jan"}, {"DDD", ""}, {"ddd", "Mon"}, {"NNN", ""},
+	{"YY", "06"}, {"MM", "01"}, {"DD", "02"}, {"Do", ""}, {"dd", ""}, {"HH", "15"}, {"hh", "03"},
+	{"kk", ""}, {"mm", "04"}, {"ss", "05"}, {"ZZ", "-0700"}, {"zz", "MST"}, {"Mo", ""}, {"Qo", ""},
+	{"wo", ""}, {"ww", ""}, {"Wo", ""}, {"WW", ""}, {"gg", ""}, {"GG", ""}, {"NN", ""},
+	{"M", "1"}, {"D", "2"}, {"H", ""}, {"h", "3"}, {"k", ""}, {"m", "4"}, {"s", "5"}, {"A", "PM"}, {"a", "pm"},
+	{"Z", "-07:00"}, {"z", "MST"}, {"Y", ""}, {"Q", ""}, {"d", ""}, {"e", ""}, {"E", ""}, {"w", ""},
+	{"W", ""}, {"X", ""}, {"x", ""}, {"N", ""},
+}
+
+// momentElements reads moment.js tokens, text in square brackets is literal
+func momentElements(pattern string) ([]layoutElement, error) {
+	var elements []layoutElement
+	for i := 0; i < len(pattern); {
+		rest := pattern[i:]
+		if strings.HasPrefix(rest, "[") {
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [ in %q", pattern)
+			}
+			elements = append(elements, literal(rest[1:end]))
+			i += end + 1
+			continue
+		}
+		if rest[0] == 'S' {
+			n := len(rest) - len(strings.TrimLeft(rest, "S"))
+			elements = append(elements, fraction(n, rest[:n]))
+			i += n
+			continue
+		}
+
+		matched := false
+		for _, t := range momentTokens {
+			if !strings.HasPrefix(rest, t.token) {
+				continue
+			}
+			if t.layout == "" {
+				return nil, fmt.Errorf("token %q has no layout element", t.token)
+			}
+			elements = append(elements, element(t.layout))
+			i += len(t.token)
+			matched = true
+			break
+		}
+		if matched {
+			continue
+		}
+		if c := rest[0]; c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+			return nil, fmt.Errorf("token %q has no layout element, put literal text in [ ]", c)
+		}
+		elements = append(elements, literal(rest[:1]))
+		i++
+	}
+	return elements, nil
+}
+
+// strftimeLayouts are the layout elements of strftime conversions, with the - flag for no padding
+// and the : flag for offsets with a colon. Fractions are the digits of %f, %L and %N.
+var strftimeLayouts = map[string]string{
+	"Y": "2006", "y": "06", "m": "01", "-m": "1", "d": "02", "-d": "2", "e": "_2", "j": "002",
+	"H": "15", "I": "03", "-I": "3", "M": "04", "-M": "4", "S": "05", "-S": "5", "p": "PM", "P": "pm",
+	"b": "Jan", "h": "Jan", "B": "January", "a": "Mon", "A": "Monday", "Z": "MST", "z": "-0700", ":z": "-07:00",
+	"T": "15:04:05", "D": "01/02/06", "F": "2006-01-02", "R": "15:04", "c": "Mon Jan _2 15:04:05 2006",
+	"x": "01/02/06", "X": "15:04:05",
+}
+
+// strftimeFractions are the digits of the fraction conversions
+var strftimeFractions = map[string]int{"f": 6, "L": 3, "N": 9}
+
+// strftimeElements reads strftime conversions, other text is literal
+func strftimeElements(pattern string) ([]layoutElement, error) {
+	var elements []layoutElement
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c != '%' {
+			elements = append(elements, literal(pattern[i:i+1]))
+			continue
+		}
+		conv := ""
+		for i++; i < len(pattern); i++ {
+			conv += pattern[i : i+1]
+			if pattern[i] != '-' && pattern[i] != ':' {
+				break
+			}
+		}
+		switch {
+		case conv == "":
+			return nil, fmt.Errorf("%q ends with a lone %%", pattern)
+		case conv == "%":
+			elements = append(elements, literal("%"))
+		case conv == "n":
+			elements = append(elements, literal("\n"))
+		case conv == "t":
+			elements = append(elements, literal("\t"))
+		case strftimeFractions[conv] > 0:
+			elements = append(elements, fraction(strftimeFractions[conv], "%"+conv))
+		case strftimeLayouts[conv] != "":
+			elements = append(elements, element(strftimeLayouts[conv]))
+		default:
+			return nil, fmt.Errorf("conversion %%%s has no layout element", conv)
+		}
+	}
+	return elements, nil
+}
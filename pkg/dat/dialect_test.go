@@ -0,0 +1,114 @@
+package dat
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDialect(t *testing.T) {
+	tests := []struct {
+		name  string
+		want  Dialect
+		error bool
+	}{
+		{"", DialectGo, false},
+		{"go", DialectGo, false},
+		{"Strftime", DialectStrftime, false},
+		{"python", DialectStrftime, false},
+		{"ICU", DialectJava, false},
+		{"dayjs", DialectMoment, false},
+		{"klingon", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseDialect(test.name)
+			if test.error {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestTranslateLayout(t *testing.T) {
+	tests := []struct {
+		format  string
+		dialect Dialect
+		want    string
+		err     string
+	}{
+		{"2006-01-02", DialectGo, "2006-01-02", ""},
+		{"%Y-%m-%d", DialectGo, "%Y-%m-%d", ""},
+		{"rfc3339", DialectStrftime, "2006-01-02T15:04:05Z07:00", ""},
+		{"kitchen", DialectMoment, "3:04PM", ""},
+		{"%Y-%m-%dT%H:%M:%S.%f%:z", DialectStrftime, "2006-01-02T15:04:05.000000-07:00", ""},
+		{"%a %-d %b %y %-I:%M %p %Z", DialectStrftime, "Mon 2 Jan 06 3:04 PM MST", ""},
+		{"%F %T,%L %%", DialectStrftime, "2006-01-02 15:04:05,000 %", ""},
+		{"%j%n%t", DialectStrftime, "002\n\t", ""},
+		{"%Y %U", DialectStrftime, "", "strftime pattern: conversion %U has no layout element"},
+		{"%Y %", DialectStrftime, "", `strftime pattern: "%Y %" ends with a lone %`},
+		{"%H%f", DialectStrftime, "", `strftime pattern: fraction "%f" is only supported after a . or , separator`},
+		{"yyyy-MM-dd'T'HH:mm", DialectJava, "2006-01-02T15:04", ""},
+		{"yyyy QQQ", DialectJava, "", `java pattern: pattern letter 'Q' has no layout element`},
+		{"YYYY-MM-DD HH:mm:ss.SSS Z", DialectMoment, "2006-01-02 15:04:05.000 -07:00", ""},
+		{"dddd, MMMM D YYYY h:mm a [at] zz", DialectMoment, "Monday, January 2 2006 3:04 pm at MST", ""},
+		{"MMM Do", DialectMoment, "", `moment pattern: token "Do" has no layout element`},
+		{"YYYY T", DialectMoment, "", `moment pattern: token 'T' has no layout element, put literal text in [ ]`},
+		{"YYYY [T", DialectMoment, "", `moment pattern: unterminated [ in "YYYY [T"`},
+		{"YYYY [Jan]", DialectMoment, "", `moment pattern: literal "Jan" would be read as a layout element`},
+	}
+	for _, test := range tests {
+		t.Run(string(test.dialect)+" "+test.format, func(t *testing.T) {
+			got, err := TranslateLayout(test.format, test.dialect)
+			if test.err != "" {
+				assert.ErrorIs(t, err, ErrInvalidLayout)
+				var pe *ParseError
+				if assert.True(t, errors.As(err, &pe)) {
+					assert.Equal(t, []string{test.err}, pe.Suggestions)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+	_, err := TranslateLayout("%Y", "klingon")
+	assert.Error(t, err)
+}
+
+func TestTranslateJava(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+		err     string
+	}{
+		{"yyyy-MM-dd", "2006-01-02", ""},
+		{"yyyy-MM-dd'T'HH:mm:ss.SSSXXX", "2006-01-02T15:04:05.000Z07:00", ""},
+		{"EEE, d MMM yy h:mm a z", "Mon, 2 Jan 06 3:04 PM MST", ""},
+		{"EEEE MMMM dd, uuuu HH:mm:ss,SSSSSS Z", "Monday January 02, 2006 15:04:05,000000 -0700", ""},
+		{"DDD xx", "002 -0700", ""},
+		{"hh 'o''clock'", "03 o'clock", ""},
+		{"yyyy ''", "2006 '", ""},
+		{"yyyy QQQ", "", `pattern letter 'Q' has no layout element`},
+		{"H:mm", "", `pattern "H" has no layout element`},
+		{"ssSSS", "", `fraction "SSS" is only supported after a . or , separator`},
+		{"yyyy 'Jan'", "", `literal "Jan" would be read as a layout element`},
+		{"yyyy 1", "", `literal "1" would be read as a layout element`},
+		{"yyyy 'T", "", `unterminated quote in "yyyy 'T"`},
+	}
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			got, err := TranslateJava(test.pattern)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
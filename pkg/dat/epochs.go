@@ -0,0 +1,57 @@
+package dat
+
+import (
+	"math/big"
+	"time"
+)
+
+// EpochUnit is a unit a unix epoch is counted in
+type EpochUnit string
+
+const (
+	// UnitSeconds seconds, as unix and most c libraries count
+	UnitSeconds EpochUnit = "s"
+	// UnitMilliseconds milliseconds, as java and javascript count
+	UnitMilliseconds EpochUnit = "ms"
+	// UnitMicroseconds microseconds, as postgres and python count
+	UnitMicroseconds EpochUnit = "us"
+	// UnitNanoseconds nanoseconds, as go counts
+	UnitNanoseconds EpochUnit = "ns"
+)
+
+// epochUnits are the units of Epochs with their length in nanoseconds
+var epochUnits = []struct {
+	unit  EpochUnit
+	nanos int64
+}{
+	{UnitSeconds, int64(time.Second)},
+	{UnitMilliseconds, int64(time.Millisecond)},
+	{UnitMicroseconds, int64(time.Microsecond)},
+	{UnitNanoseconds, 1},
+}
+
+// EpochValue is a time as a unix epoch in a unit
+type EpochValue struct {
+	Unit  EpochUnit `json:"unit"`
+	Value string    `json:"value"`
+	// Hex is Value in hexadecimal with a 0x prefix
+	Hex string `json:"hex"`
+}
+
+// Epochs returns the time as a unix epoch in seconds, milliseconds, microseconds and nanoseconds.
+// Values are floored, so instants before 1970 count down, and do not overflow.
+func Epochs(tm time.Time) []EpochValue {
+	nanos := new(big.Int).Mul(big.NewInt(tm.Unix()), bigNanosPerSecond)
+	nanos.Add(nanos, big.NewInt(int64(tm.Nanosecond())))
+
+	values := make([]EpochValue, 0, len(epochUnits))
+	for _, u := range epochUnits {
+		n := new(big.Int).Div(nanos, big.NewInt(u.nanos))
+		hex := "0x" + new(big.Int).Abs(n).Text(16)
+		if n.Sign() < 0 {
+			hex = "-" + hex
+		}
+		values = append(values, EpochValue{Unit: u.unit, Value: n.String(), Hex: hex})
+	}
+	return values
+}
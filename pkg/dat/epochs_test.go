@@ -0,0 +1,40 @@
+package dat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEpochs(t *testing.T) {
+	tests := []struct {
+		name string
+		time time.Time
+		want []EpochValue
+	}{
+		{"epoch", time.Unix(1700000000, 123456789), []EpochValue{
+			{UnitSeconds, "1700000000", "0x6553f100"},
+			{UnitMilliseconds, "1700000000123", "0x18bcfe5687b"},
+			{UnitMicroseconds, "1700000000123456", "0x60a2418202240"},
+			{UnitNanoseconds, "1700000000123456789", "0x17979cfe3d85cd15"},
+		}},
+		{"before 1970", time.Unix(-1, 500000000), []EpochValue{
+			{UnitSeconds, "-1", "-0x1"},
+			{UnitMilliseconds, "-500", "-0x1f4"},
+			{UnitMicroseconds, "-500000", "-0x7a120"},
+			{UnitNanoseconds, "-500000000", "-0x1dcd6500"},
+		}},
+		{"beyond int64 nanoseconds", time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC), []EpochValue{
+			{UnitSeconds, "253402300799", "0x3afff4417f"},
+			{UnitMilliseconds, "253402300799000", "0xe677d21fd818"},
+			{UnitMicroseconds, "253402300799000000", "0x384440ccc641dc0"},
+			{UnitNanoseconds, "253402300799000000000", "0xdbca9d1fe67143600"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, Epochs(test.time))
+		})
+	}
+}
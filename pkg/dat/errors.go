@@ -0,0 +1,97 @@
+package dat
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNotAnEpoch input could not be read as an epoch
+	ErrNotAnEpoch = errors.New("is not a valid epoch")
+	// ErrOutOfRange input is a number that cannot be represented as a time
+	ErrOutOfRange = errors.New("is out of range")
+	// ErrUnknownFormat input did not match any supported time format
+	ErrUnknownFormat = errors.New("is not a known time format")
+	// ErrAmbiguous input matched several time formats that disagree on the time
+	ErrAmbiguous = errors.New("is ambiguous")
+
+	// ErrInvalidEpoch input could not be read as an epoch.
+	//
+	// Deprecated: use ErrNotAnEpoch, or ErrOutOfRange for numbers that cannot be a time.
+	ErrInvalidEpoch = ErrNotAnEpoch
+	// ErrInvalidTimeFormat input did not match any supported time format.
+	//
+	// Deprecated: use ErrUnknownFormat, or ErrAmbiguous for input that matched several formats.
+	ErrInvalidTimeFormat = ErrUnknownFormat
+)
+
+// ParseError is returned when input cannot be converted to a time.
+// Err is one of the Err* values above and can be checked with errors.Is.
+type ParseError struct {
+	// Input is the offending input
+	Input string
+	// Pos is the byte offset in Input where parsing failed, -1 when not applicable.
+	Pos int
+	// Suggestions are possible fixes for the input, may be empty.
+	Suggestions []string
+	Err         error
+}
+
+// Error implements error
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("%q %v", TruncateString(e.Input, 20), e.Err)
+	if e.Pos >= 0 {
+		msg += fmt.Sprintf(" (at offset %d)", e.Pos)
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// notAnEpoch builds the error for input that is not a decimal integer.
+func notAnEpoch(input string) *ParseError {
+	return notAnEpochRadix(input, 10)
+}
+
+// notAnEpochRadix builds the error for input that is not an integer in the radix, see ParseInteger.
+func notAnEpochRadix(input string, radix int) *ParseError {
+	pe := &ParseError{Input: input, Pos: -1, Err: ErrNotAnEpoch}
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" {
+		pe.Suggestions = append(pe.Suggestions, "provide an epoch, input is empty")
+		return pe
+	}
+
+	sign, digits, r := splitRadix(trimmed, radix)
+	offset := strings.Index(input, trimmed) + len(trimmed) - len(digits)
+	if digits == "" {
+		pe.Pos = offset
+	}
+	for i, c := range digits {
+		if v, err := strconv.ParseUint(string(c), 36, 8); err != nil || int(v) >= r {
+			pe.Pos = offset + i
+			break
+		}
+	}
+	if r != 10 || sign+digits != trimmed {
+		if pe.Pos >= 0 {
+			pe.Suggestions = append(pe.Suggestions, fmt.Sprintf("%q is not a base %d digit", input[pe.Pos], r))
+		}
+		return pe
+	}
+
+	if _, layout, err := ParseTime(trimmed); err == nil {
+		name, _ := FormatName(layout)
+		pe.Suggestions = append(pe.Suggestions, fmt.Sprintf("input looks like a %s time, parse it as a time format", name))
+	} else if strings.Count(trimmed, ".") == 1 {
+		pe.Suggestions = append(pe.Suggestions, "fractional epochs are not supported, remove the fraction")
+	} else if strings.ContainsAny(trimmed, ",_ ") {
+		pe.Suggestions = append(pe.Suggestions, "remove digit separators")
+	}
+	return pe
+}
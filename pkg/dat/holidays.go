@@ -0,0 +1,250 @@
+package dat
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxHolidaySpan is the longest event read from a calendar, in days
+const maxHolidaySpan = 366
+
+// LoadHolidays adds the holidays of an iCalendar (.ics) or YAML (.yaml, .yml) file to the calendar.
+func (b *BusinessCalendar) LoadHolidays(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ics", ".ical", ".ifb":
+		err = b.ReadICal(f)
+	case ".yaml", ".yml":
+		err = b.ReadHolidaysYAML(f)
+	default:
+		return fmt.Errorf("%s: unknown holiday calendar type, expected .ics, .yaml or .yml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// holidaysFile is the YAML holiday calendar format
+//
+//	weekend: [fri, sat]
+//	holidays:
+//	  - date: 2024-12-25
+//	    name: Christmas Day
+//	  - date: 01-01
+//	    name: New Year's Day
+type holidaysFile struct {
+	Weekend  []string `yaml:"weekend"`
+	Holidays []struct {
+		Date string `yaml:"date"`
+		Name string `yaml:"name"`
+	} `yaml:"holidays"`
+}
+
+// ReadHolidaysYAML adds the holidays of a YAML calendar. Dates are 2006-01-02, or 01-02 for
+// a holiday every year. A weekend list replaces the weekend of the calendar.
+func (b *BusinessCalendar) ReadHolidaysYAML(r io.Reader) error {
+	var file holidaysFile
+	if err := yaml.NewDecoder(r).Decode(&file); err != nil && err != io.EOF {
+		return err
+	}
+	if file.Weekend != nil {
+		weekend, err := ParseWeekend(strings.Join(file.Weekend, ","))
+		if err != nil {
+			return err
+		}
+		b.SetWeekend(weekend)
+	}
+	for _, h := range file.Holidays {
+		if tm, err := time.Parse("2006-01-02", h.Date); err == nil {
+			b.AddHoliday(tm, h.Name)
+			continue
+		}
+		tm, err := time.Parse("01-02", h.Date)
+		if err != nil {
+			return fmt.Errorf("invalid holiday date %q, expected 2006-01-02 or 01-02", h.Date)
+		}
+		b.yearly = append(b.yearly, yearlyHoliday{month: tm.Month(), day: tm.Day(), name: h.Name, interval: 1})
+	}
+	return nil
+}
+
+// icalEvent is the part of a VEVENT that makes a holiday
+type icalEvent struct {
+	summary      string
+	start, end   string
+	endExclusive bool
+	rrule        string
+}
+
+// ReadICal adds the events of an iCalendar file as holidays. Every date an event covers is a
+// holiday, and yearly recurring events repeat on the same date. Times of day are ignored.
+func (b *BusinessCalendar) ReadICal(r io.Reader) error {
+	lines, err := unfoldICal(r)
+	if err != nil {
+		return err
+	}
+	var event *icalEvent
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, params, _ := strings.Cut(strings.ToUpper(name), ";")
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			event = &icalEvent{}
+		case event == nil:
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if err := b.addICalEvent(event); err != nil {
+				return err
+			}
+			event = nil
+		case name == "SUMMARY":
+			event.summary = strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(value)
+		case name == "DTSTART":
+			event.start = value
+		case name == "DTEND":
+			// the end of all day events and of events ending at midnight is the day after
+			event.end = value
+			event.endExclusive = strings.Contains(params, "VALUE=DATE") || len(value) == 8 || strings.Contains(value, "T000000")
+		case name == "RRULE":
+			event.rrule = value
+		}
+	}
+	return nil
+}
+
+// unfoldICal reads the content lines of an iCalendar file, joining folded lines
+func unfoldICal(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// icalDate reads the date of an iCalendar DATE or DATE-TIME value
+func icalDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid iCalendar date %q", value)
+	}
+	tm, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid iCalendar date %q", value)
+	}
+	return tm, nil
+}
+
+// addICalEvent adds every date of the event, once or every year
+func (b *BusinessCalendar) addICalEvent(e *icalEvent) error {
+	if e.start == "" {
+		return fmt.Errorf("event %q has no DTSTART", e.summary)
+	}
+	start, err := icalDate(e.start)
+	if err != nil {
+		return err
+	}
+	last := start
+	if e.end != "" {
+		if last, err = icalDate(e.end); err != nil {
+			return err
+		}
+		if e.endExclusive && last.After(start) {
+			last = last.AddDate(0, 0, -1)
+		}
+	}
+	if last.Before(start) || last.Sub(start) > maxHolidaySpan*24*time.Hour {
+		return fmt.Errorf("event %q spans %s to %s", e.summary, e.start, e.end)
+	}
+
+	var yearly *yearlyHoliday
+	if e.rrule != "" {
+		if yearly, err = parseYearlyRule(e.rrule, start); err != nil {
+			return fmt.Errorf("event %q: %w", e.summary, err)
+		}
+	}
+	for i, d := 0, start; !d.After(last); i, d = i+1, d.AddDate(0, 0, 1) {
+		if yearly == nil {
+			b.AddHoliday(d, e.summary)
+			continue
+		}
+		// days past the new year fall a year after each occurrence starts,
+		// and the occurrence starting on the last date still runs to its end
+		h := *yearly
+		h.month, h.day, h.name = d.Month(), d.Day(), e.summary
+		h.from += d.Year() - start.Year()
+		if h.until.year != 0 {
+			h.until = h.until.add(i)
+		}
+		b.yearly = append(b.yearly, h)
+	}
+	return nil
+}
+
+// parseYearlyRule reads an RRULE repeating on the date of DTSTART every year or every few years.
+// BYMONTH and BYMONTHDAY must name the month and day of DTSTART.
+func parseYearlyRule(rule string, start time.Time) (*yearlyHoliday, error) {
+	from := start.Year()
+	h := &yearlyHoliday{from: from, interval: 1}
+	count := 0
+	for _, part := range strings.Split(rule, ";") {
+		key, value, _ := strings.Cut(strings.ToUpper(part), "=")
+		var err error
+		switch key {
+		case "FREQ":
+			if value != "YEARLY" {
+				return nil, fmt.Errorf("unsupported RRULE %q, only yearly rules are supported", rule)
+			}
+		case "INTERVAL":
+			if h.interval, err = strconv.Atoi(value); err != nil || h.interval < 1 {
+				return nil, fmt.Errorf("invalid RRULE interval %q", value)
+			}
+		case "COUNT":
+			if count, err = strconv.Atoi(value); err != nil || count < 1 {
+				return nil, fmt.Errorf("invalid RRULE count %q", value)
+			}
+		case "UNTIL":
+			until, err := icalDate(value)
+			if err != nil {
+				return nil, err
+			}
+			h.until = dateOf(until)
+		case "BYMONTH":
+			if n, err := strconv.Atoi(value); err != nil || n != int(start.Month()) {
+				return nil, fmt.Errorf("unsupported RRULE %q, BYMONTH must be the month of DTSTART %s", rule, start.Format("2006-01-02"))
+			}
+		case "BYMONTHDAY":
+			if n, err := strconv.Atoi(value); err != nil || n != start.Day() {
+				return nil, fmt.Errorf("unsupported RRULE %q, BYMONTHDAY must be the day of DTSTART %s", rule, start.Format("2006-01-02"))
+			}
+		case "WKST":
+			// weeks do not matter to yearly dates
+		default:
+			return nil, fmt.Errorf("unsupported RRULE %q, only yearly rules on a fixed date are supported", rule)
+		}
+	}
+	if count > 0 {
+		h.until = date{from + (count-1)*h.interval, time.December, 31}
+	}
+	return h, nil
+}
@@ -0,0 +1,131 @@
+package dat
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testICal = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20241225\r\n" +
+	"DTEND;VALUE=DATE:20241227\r\n" +
+	"SUMMARY:Christmas\\, and Boxing\r\n" +
+	"  Day\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20200101\r\n" +
+	"RRULE:FREQ=YEARLY;UNTIL=20251231T000000Z\r\n" +
+	"SUMMARY:New Year's Day\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20200704\r\n" +
+	"RRULE:FREQ=YEARLY;BYMONTH=7;BYMONTHDAY=04\r\n" +
+	"SUMMARY:Independence Day\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20261231\r\n" +
+	"DTEND;VALUE=DATE:20270102\r\n" +
+	"RRULE:FREQ=YEARLY;COUNT=2\r\n" +
+	"SUMMARY:Hogmanay\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART:20240527T090000Z\r\n" +
+	"DTEND:20240527T170000Z\r\n" +
+	"SUMMARY:Memorial Day\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestBusinessCalendar_ReadICal(t *testing.T) {
+	cal := NewBusinessCalendar()
+	require.NoError(t, cal.ReadICal(strings.NewReader(testICal)))
+
+	tests := []struct {
+		date time.Time
+		name string
+	}{
+		{time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC), "Christmas, and Boxing Day"},
+		{time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC), "Christmas, and Boxing Day"},
+		{time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC), ""},
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), "New Year's Day"},
+		{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), ""},
+		{time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), ""},
+		{time.Date(2024, 5, 27, 0, 0, 0, 0, time.UTC), "Memorial Day"},
+		{time.Date(2024, 5, 28, 0, 0, 0, 0, time.UTC), ""},
+		{time.Date(2031, 7, 4, 0, 0, 0, 0, time.UTC), "Independence Day"},
+		{time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), "Hogmanay"},
+		{time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), "Hogmanay"},
+		{time.Date(2027, 12, 31, 0, 0, 0, 0, time.UTC), "Hogmanay"},
+		{time.Date(2028, 1, 1, 0, 0, 0, 0, time.UTC), "Hogmanay"},
+		{time.Date(2028, 12, 31, 0, 0, 0, 0, time.UTC), ""},
+		{time.Date(2029, 1, 1, 0, 0, 0, 0, time.UTC), ""},
+	}
+	for _, test := range tests {
+		name, ok := cal.Holiday(test.date)
+		assert.Equal(t, test.name != "", ok, test.date)
+		assert.Equal(t, test.name, name, test.date)
+	}
+
+	for _, bad := range []string{
+		"BEGIN:VEVENT\nSUMMARY:No start\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART:2024\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20240101\nRRULE:FREQ=MONTHLY\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20240101\nRRULE:FREQ=YEARLY;BYDAY=1MO\nEND:VEVENT\n",
+		// the rule is not on the date of DTSTART
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20240101\nRRULE:FREQ=YEARLY;BYMONTH=7\nEND:VEVENT\n",
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20240101\nRRULE:FREQ=YEARLY;BYMONTH=1;BYMONTHDAY=1,15\nEND:VEVENT\n",
+	} {
+		assert.Error(t, NewBusinessCalendar().ReadICal(strings.NewReader(bad)), bad)
+	}
+}
+
+func TestBusinessCalendar_ReadHolidaysYAML(t *testing.T) {
+	cal := NewBusinessCalendar()
+	require.NoError(t, cal.ReadHolidaysYAML(strings.NewReader(`
+weekend: [fri, sat]
+holidays:
+  - date: 2024-04-10
+    name: Eid al-Fitr
+  - date: 12-02
+    name: National Day
+`)))
+	assert.Equal(t, []time.Weekday{time.Friday, time.Saturday}, cal.Weekend())
+	name, ok := cal.Holiday(time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "Eid al-Fitr", name)
+	for _, year := range []int{2024, 2031} {
+		name, ok = cal.Holiday(time.Date(year, 12, 2, 0, 0, 0, 0, time.UTC))
+		assert.True(t, ok)
+		assert.Equal(t, "National Day", name)
+	}
+
+	assert.Error(t, cal.ReadHolidaysYAML(strings.NewReader("holidays:\n  - date: tomorrow\n")))
+	assert.Error(t, cal.ReadHolidaysYAML(strings.NewReader("weekend: [someday]\n")))
+	assert.NoError(t, cal.ReadHolidaysYAML(strings.NewReader("")))
+}
+
+func TestBusinessCalendar_LoadHolidays(t *testing.T) {
+	dir := t.TempDir()
+	ics := filepath.Join(dir, "holidays.ics")
+	require.NoError(t, os.WriteFile(ics, []byte(testICal), 0o600))
+	yml := filepath.Join(dir, "holidays.yml")
+	require.NoError(t, os.WriteFile(yml, []byte("holidays:\n  - date: 2024-07-04\n    name: Independence Day\n"), 0o600))
+	txt := filepath.Join(dir, "holidays.txt")
+	require.NoError(t, os.WriteFile(txt, []byte("2024-07-04"), 0o600))
+
+	cal := NewBusinessCalendar()
+	assert.NoError(t, cal.LoadHolidays(ics))
+	assert.NoError(t, cal.LoadHolidays(yml))
+	assert.False(t, cal.IsBusinessDay(time.Date(2024, 7, 4, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, cal.IsBusinessDay(time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, cal.IsBusinessDay(time.Date(2024, 7, 5, 0, 0, 0, 0, time.UTC)))
+
+	assert.Error(t, cal.LoadHolidays(txt))
+	assert.Error(t, cal.LoadHolidays(filepath.Join(dir, "missing.ics")))
+}
@@ -0,0 +1,313 @@
+package dat
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IDKind is a kind of identifier with an embedded timestamp
+type IDKind string
+
+const (
+	// IDSnowflake Twitter/Discord style 64 bit ids, 41 bits of milliseconds since a custom epoch
+	IDSnowflake IDKind = "snowflake"
+	// IDULID 26 character Crockford base32 ids, 48 bits of unix milliseconds
+	IDULID IDKind = "ulid"
+	// IDUUID version 1, 6 and 7 uuids
+	IDUUID IDKind = "uuid"
+	// IDObjectID MongoDB ObjectIDs, 32 bits of unix seconds
+	IDObjectID IDKind = "objectid"
+	// IDKSUID 27 character base62 ids, 32 bits of seconds since 2014-05-13
+	IDKSUID IDKind = "ksuid"
+)
+
+// IDKinds are the supported id kinds
+var IDKinds = []IDKind{IDSnowflake, IDULID, IDUUID, IDObjectID, IDKSUID}
+
+// SnowflakeEpochs are the well known snowflake epochs in unix milliseconds
+var SnowflakeEpochs = map[string]int64{
+	"twitter":   1288834974657,
+	"discord":   1420070400000,
+	"instagram": 1314220021721,
+	"unix":      0,
+}
+
+// DefaultSnowflakeEpoch is the twitter snowflake epoch
+var DefaultSnowflakeEpoch = SnowflakeEpochs["twitter"]
+
+const (
+	// gregorianToUnix is the number of 100ns intervals between the uuid epoch 1582-10-15 and the unix epoch
+	gregorianToUnix = 122192928000000000
+	// ksuidEpoch is the ksuid epoch in unix seconds
+	ksuidEpoch = 1400000000
+
+	crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	base62    = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// ErrNotAnID input is not an identifier of the requested kind
+var ErrNotAnID = errors.New("is not a valid id")
+
+// IDComponent is a named part of a decoded id
+type IDComponent struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ID is a decoded identifier
+type ID struct {
+	Kind       IDKind        `json:"kind"`
+	Time       time.Time     `json:"time"`
+	Components []IDComponent `json:"components"`
+}
+
+func (id *ID) add(name, value string) {
+	id.Components = append(id.Components, IDComponent{Name: name, Value: value})
+}
+
+// ParseSnowflakeEpoch reads a snowflake epoch as a well known name or unix milliseconds.
+func ParseSnowflakeEpoch(s string) (int64, error) {
+	if ms, ok := SnowflakeEpochs[strings.ToLower(s)]; ok {
+		return ms, nil
+	}
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unknown snowflake epoch %q", s)
+	}
+	return ms, nil
+}
+
+// ParseID decodes the input as an id of the given kind.
+// snowflakeEpoch is the epoch of snowflake ids in unix milliseconds.
+func ParseID(input string, kind IDKind, snowflakeEpoch int64) (*ID, error) {
+	s := strings.TrimSpace(input)
+	var (
+		id  *ID
+		err error
+	)
+	switch kind {
+	case IDSnowflake:
+		id, err = parseSnowflake(s, snowflakeEpoch)
+	case IDULID:
+		id, err = parseULID(s)
+	case IDUUID:
+		id, err = parseUUID(s)
+	case IDObjectID:
+		id, err = parseObjectID(s)
+	case IDKSUID:
+		id, err = parseKSUID(s)
+	default:
+		return nil, fmt.Errorf("unknown id kind %q", kind)
+	}
+	if err != nil {
+		return nil, &ParseError{Input: input, Pos: -1, Err: ErrNotAnID, Suggestions: []string{err.Error()}}
+	}
+	return id, nil
+}
+
+// DetectID decodes the input as whichever id kind it looks like.
+// Snowflakes look like epochs and are not detected, see Converter.Parse.
+func DetectID(input string) (*ID, error) {
+	s := strings.TrimSpace(input)
+	var kind IDKind
+	switch {
+	case isHex(s) && len(s) == 24:
+		kind = IDObjectID
+	case len(s) == 26 && strings.IndexFunc(strings.ToUpper(s), notIn(crockford)) < 0:
+		kind = IDULID
+	case len(s) == 27 && strings.IndexFunc(s, notIn(base62)) < 0:
+		kind = IDKSUID
+	case uuidHex(s) != "":
+		kind = IDUUID
+	default:
+		return nil, &ParseError{Input: input, Pos: -1, Err: ErrNotAnID}
+	}
+	return ParseID(input, kind, DefaultSnowflakeEpoch)
+}
+
+// snowflakeDigits are the fewest digits of a detected snowflake, integers this long are not
+// plausible epochs in seconds, milliseconds or microseconds
+const snowflakeDigits = 17
+
+// detectSnowflake decodes an integer too long to be a plausible epoch as a snowflake,
+// when the time it holds is plausible
+func detectSnowflake(input string, epoch int64) (*ID, bool) {
+	s := strings.TrimSpace(input)
+	if len(s) < snowflakeDigits || strings.IndexFunc(s, notIn("0123456789")) >= 0 {
+		return nil, false
+	}
+	id, err := parseSnowflake(s, epoch)
+	if err != nil || !plausible(id.Time) {
+		return nil, false
+	}
+	return id, true
+}
+
+func notIn(chars string) func(rune) bool {
+	return func(r rune) bool {
+		return !strings.ContainsRune(chars, r)
+	}
+}
+
+func isHex(s string) bool {
+	return s != "" && strings.IndexFunc(strings.ToLower(s), notIn("0123456789abcdef")) < 0
+}
+
+func parseSnowflake(s string, epoch int64) (*ID, error) {
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return nil, errors.New("snowflakes are unsigned 64 bit integers")
+	}
+	ms := int64(v>>22) + epoch
+	id := &ID{Kind: IDSnowflake, Time: time.UnixMilli(ms)}
+	id.add("timestamp", strconv.FormatUint(v>>22, 10))
+	id.add("epoch", strconv.FormatInt(epoch, 10))
+	id.add("machine", strconv.FormatUint((v>>12)&0x3ff, 10))
+	id.add("sequence", strconv.FormatUint(v&0xfff, 10))
+	return id, nil
+}
+
+func parseULID(s string) (*ID, error) {
+	if len(s) != 26 {
+		return nil, errors.New("ulids are 26 characters")
+	}
+	s = strings.ToUpper(s)
+	if s[0] > '7' {
+		return nil, errors.New("ulid timestamp overflows 48 bits")
+	}
+	var ms int64
+	for _, r := range s[:10] {
+		i := strings.IndexRune(crockford, r)
+		if i < 0 {
+			return nil, fmt.Errorf("%q is not a crockford base32 character", r)
+		}
+		ms = ms<<5 | int64(i)
+	}
+	if strings.IndexFunc(s[10:], notIn(crockford)) >= 0 {
+		return nil, errors.New("ulid randomness is not crockford base32")
+	}
+	id := &ID{Kind: IDULID, Time: time.UnixMilli(ms)}
+	id.add("timestamp", strconv.FormatInt(ms, 10))
+	id.add("randomness", s[10:])
+	return id, nil
+}
+
+// uuidHex returns the 32 hex digits of a uuid in any of its common notations, empty if s is not a uuid
+func uuidHex(s string) string {
+	s = strings.TrimPrefix(strings.ToLower(s), "urn:uuid:")
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}")
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return ""
+		}
+		s = strings.ReplaceAll(s, "-", "")
+	}
+	if len(s) != 32 || !isHex(s) {
+		return ""
+	}
+	return s
+}
+
+func parseUUID(s string) (*ID, error) {
+	h := uuidHex(s)
+	if h == "" {
+		return nil, errors.New("uuids are 32 hex digits")
+	}
+	b, _ := hex.DecodeString(h)
+	version := b[6] >> 4
+
+	id := &ID{Kind: IDUUID}
+	id.add("version", strconv.Itoa(int(version)))
+	id.add("variant", uuidVariant(b[8]))
+
+	switch version {
+	case 1, 6:
+		var ts uint64
+		if version == 1 {
+			ts = uint64(binary.BigEndian.Uint16(b[6:8])&0x0fff)<<48 |
+				uint64(binary.BigEndian.Uint16(b[4:6]))<<32 |
+				uint64(binary.BigEndian.Uint32(b[0:4]))
+		} else {
+			ts = uint64(binary.BigEndian.Uint32(b[0:4]))<<28 |
+				uint64(binary.BigEndian.Uint16(b[4:6]))<<12 |
+				uint64(binary.BigEndian.Uint16(b[6:8])&0x0fff)
+		}
+		intervals := int64(ts) - gregorianToUnix
+		id.Time = time.Unix(intervals/1e7, intervals%1e7*100)
+		id.add("timestamp", strconv.FormatUint(ts, 10))
+		id.add("clock sequence", strconv.Itoa(int(binary.BigEndian.Uint16(b[8:10])&0x3fff)))
+		id.add("node", formatNode(b[10:]))
+	case 7:
+		ms := int64(binary.BigEndian.Uint64(append([]byte{0, 0}, b[0:6]...)))
+		id.Time = time.UnixMilli(ms)
+		id.add("timestamp", strconv.FormatInt(ms, 10))
+		id.add("random", h[13:16]+h[17:])
+	default:
+		return nil, fmt.Errorf("uuid version %d has no timestamp", version)
+	}
+	return id, nil
+}
+
+func uuidVariant(b byte) string {
+	switch {
+	case b&0x80 == 0:
+		return "NCS"
+	case b&0xc0 == 0x80:
+		return "RFC 4122"
+	case b&0xe0 == 0xc0:
+		return "Microsoft"
+	default:
+		return "future"
+	}
+}
+
+func formatNode(b []byte) string {
+	parts := make([]string, len(b))
+	for i := range b {
+		parts[i] = hex.EncodeToString(b[i : i+1])
+	}
+	return strings.Join(parts, ":")
+}
+
+func parseObjectID(s string) (*ID, error) {
+	if len(s) != 24 || !isHex(s) {
+		return nil, errors.New("object ids are 24 hex digits")
+	}
+	b, _ := hex.DecodeString(s)
+	sec := binary.BigEndian.Uint32(b[0:4])
+	id := &ID{Kind: IDObjectID, Time: time.Unix(int64(sec), 0)}
+	id.add("timestamp", strconv.FormatUint(uint64(sec), 10))
+	id.add("random", hex.EncodeToString(b[4:9]))
+	id.add("counter", strconv.FormatUint(uint64(b[9])<<16|uint64(b[10])<<8|uint64(b[11]), 10))
+	return id, nil
+}
+
+func parseKSUID(s string) (*ID, error) {
+	if len(s) != 27 {
+		return nil, errors.New("ksuids are 27 base62 characters")
+	}
+	n := new(big.Int)
+	for _, r := range s {
+		i := strings.IndexRune(base62, r)
+		if i < 0 {
+			return nil, fmt.Errorf("%q is not a base62 character", r)
+		}
+		n.Mul(n, big.NewInt(62))
+		n.Add(n, big.NewInt(int64(i)))
+	}
+	if n.BitLen() > 160 {
+		return nil, errors.New("ksuid overflows 160 bits")
+	}
+	b := n.FillBytes(make([]byte, 20))
+	sec := binary.BigEndian.Uint32(b[0:4])
+	id := &ID{Kind: IDKSUID, Time: time.Unix(int64(sec)+ksuidEpoch, 0)}
+	id.add("timestamp", strconv.FormatUint(uint64(sec), 10))
+	id.add("payload", hex.EncodeToString(b[4:]))
+	return id, nil
+}
@@ -0,0 +1,149 @@
+package dat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseID(t *testing.T) {
+	rfcExample := time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+	tests := []struct {
+		name  string
+		input string
+		kind  IDKind
+		want  time.Time
+		error bool
+	}{
+		{"snowflake", "175928847299117063", IDSnowflake, time.UnixMilli(1462015105796), false},
+		{"ulid", "01ARYZ6S41TSV4RRFFQ69G5FAV", IDULID, time.UnixMilli(1469918176385), false},
+		{"ulid lowercase", "01aryz6s41tsv4rrffq69g5fav", IDULID, time.UnixMilli(1469918176385), false},
+		{"uuid v1", "c232ab00-9414-11ec-b3c8-9f6bdeced846", IDUUID, rfcExample, false},
+		{"uuid v6", "1EC9414C-232A-6B00-B3C8-9F6BDECED846", IDUUID, rfcExample, false},
+		{"uuid v7", "017F22E2-79B0-7CC3-98C4-DC0C0C07398F", IDUUID, rfcExample, false},
+		{"uuid v7 urn", "urn:uuid:017f22e279b07cc398c4dc0c0c07398f", IDUUID, rfcExample, false},
+		{"objectid", "507f1f77bcf86cd799439011", IDObjectID, time.Unix(1350508407, 0), false},
+		{"ksuid", "0ujtsYcgvSTl8PAuAdqWYSMnLOv", IDKSUID, time.Unix(1507608047, 0), false},
+		{"uuid v4", "f47ac10b-58cc-4372-a567-0e02b2c3d479", IDUUID, time.Time{}, true},
+		{"bad ulid", "81ARYZ6S41TSV4RRFFQ69G5FAV", IDULID, time.Time{}, true},
+		{"bad snowflake", "-1", IDSnowflake, time.Time{}, true},
+		{"bad objectid", "507f1f77bcf86cd79943901z", IDObjectID, time.Time{}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseID(test.input, test.kind, SnowflakeEpochs["discord"])
+			if test.error {
+				assert.ErrorIs(t, err, ErrNotAnID)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.kind, got.Kind)
+			assert.True(t, test.want.Equal(got.Time), "want:%s got:%s", test.want, got.Time)
+		})
+	}
+}
+
+func TestParseID_Components(t *testing.T) {
+	got, err := ParseID("175928847299117063", IDSnowflake, SnowflakeEpochs["discord"])
+	assert.NoError(t, err)
+	assert.Equal(t, []IDComponent{
+		{"timestamp", "41944705796"},
+		{"epoch", "1420070400000"},
+		{"machine", "32"},
+		{"sequence", "7"},
+	}, got.Components)
+
+	got, err = ParseID("c232ab00-9414-11ec-b3c8-9f6bdeced846", IDUUID, 0)
+	assert.NoError(t, err)
+	assert.Contains(t, got.Components, IDComponent{"node", "9f:6b:de:ce:d8:46"})
+	assert.Contains(t, got.Components, IDComponent{"version", "1"})
+}
+
+func TestDetectID(t *testing.T) {
+	tests := []struct {
+		input string
+		want  IDKind
+	}{
+		{"01ARYZ6S41TSV4RRFFQ69G5FAV", IDULID},
+		{"{017F22E2-79B0-7CC3-98C4-DC0C0C07398F}", IDUUID},
+		{"507f1f77bcf86cd799439011", IDObjectID},
+		{"0ujtsYcgvSTl8PAuAdqWYSMnLOv", IDKSUID},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			got, err := DetectID(test.input)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got.Kind)
+		})
+	}
+
+	_, err := DetectID("not an id")
+	assert.ErrorIs(t, err, ErrNotAnID)
+}
+
+func TestParseSnowflakeEpoch(t *testing.T) {
+	got, err := ParseSnowflakeEpoch("Discord")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1420070400000), got)
+
+	got, err = ParseSnowflakeEpoch("1000")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), got)
+
+	_, err = ParseSnowflakeEpoch("myspace")
+	assert.Error(t, err)
+}
+
+func TestConverter_ParseID(t *testing.T) {
+	p, err := NewConverter().Parse("507f1f77bcf86cd799439011")
+	assert.NoError(t, err)
+	if assert.NotNil(t, p.ID) {
+		assert.Equal(t, IDObjectID, p.ID.Kind)
+	}
+	assert.True(t, time.Unix(1350508407, 0).Equal(p.Time))
+
+	p, err = NewConverter(WithID(IDSnowflake), WithSnowflakeEpoch(SnowflakeEpochs["discord"])).Parse("175928847299117063")
+	assert.NoError(t, err)
+	assert.True(t, time.UnixMilli(1462015105796).Equal(p.Time))
+
+	res, err := NewConverter().Convert("01ARYZ6S41TSV4RRFFQ69G5FAV")
+	assert.NoError(t, err)
+	assert.Equal(t, IDULID, res.ID.Kind)
+
+	_, err = NewConverter().Parse("qqqq")
+	assert.ErrorIs(t, err, ErrNotAnEpoch)
+}
+
+func TestConverter_DetectSnowflake(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		input string
+		want  time.Time
+	}{
+		{"twitter", nil, "1541815603606036480", time.UnixMilli(int64(1541815603606036480>>22) + DefaultSnowflakeEpoch)},
+		{"discord", []Option{WithSnowflakeEpoch(SnowflakeEpochs["discord"])}, "175928847299117063", time.UnixMilli(1462015105796)},
+		{"beyond int64", nil, "10000000000000000000", time.UnixMilli(int64(uint64(10000000000000000000)>>22) + DefaultSnowflakeEpoch)},
+		{"milliseconds precision", []Option{WithPrecision(Milliseconds)}, "1541815603606036480", time.UnixMilli(int64(1541815603606036480>>22) + DefaultSnowflakeEpoch)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := NewConverter(test.opts...).Parse(test.input)
+			assert.NoError(t, err)
+			if assert.NotNil(t, p.ID) {
+				assert.Equal(t, IDSnowflake, p.ID.Kind)
+			}
+			assert.True(t, test.want.Equal(p.Time), "want:%s got:%s", test.want, p.Time)
+		})
+	}
+
+	// shorter integers are epochs, in microseconds here
+	p, err := NewConverter().Parse("1700000000000000")
+	assert.NoError(t, err)
+	assert.Nil(t, p.ID)
+	// as are integers read in an explicit radix
+	p, err = NewConverter(WithRadix(10)).Parse("1541815603606036480")
+	assert.NoError(t, err)
+	assert.Nil(t, p.ID)
+}
@@ -0,0 +1,60 @@
+package dat
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// ErrInvalidLayout a format has no layout elements, so every time formats the same
+var ErrInvalidLayout = errors.New("is not a valid layout")
+
+// layoutProbe differs from the reference time in every element a layout can have, so
+// formatting it changes every layout with elements
+var layoutProbe = time.Date(1999, time.November, 28, 7, 9, 8, 987654321, time.FixedZone("XYZ", -(8*3600+30*60)))
+
+// outputLayout is the layout FormatOutput uses for format, and whether it fell back to DateFormat
+func outputLayout(format string) (string, bool) {
+	layout := format
+	if l, ok := LookupFormat(format); ok {
+		layout = l
+	}
+	if !hasLayoutElements(layout) {
+		return DateFormat, true
+	}
+	return layout, false
+}
+
+// hasLayoutElements reports whether the layout has any element of the reference time
+func hasLayoutElements(layout string) bool {
+	return layoutProbe.Format(layout) != layout
+}
+
+// ValidateLayout checks that format, a layout or the name of a supported format, has
+// layout elements. FormatOutput falls back to DateFormat for formats that fail.
+// The error suggests the layout of formats that look like Java or ICU patterns.
+func ValidateLayout(format string) error {
+	if _, fallback := outputLayout(format); !fallback {
+		return nil
+	}
+	pe := &ParseError{Input: format, Pos: -1, Err: ErrInvalidLayout}
+	if LooksLikeJava(format) {
+		if layout, err := TranslateJava(format); err == nil {
+			pe.Suggestions = append(pe.Suggestions, fmt.Sprintf("looks like a java pattern, the layout is %q", layout))
+		} else {
+			pe.Suggestions = append(pe.Suggestions, fmt.Sprintf("looks like a java pattern, but %s", err))
+		}
+	}
+	pe.Suggestions = append(pe.Suggestions,
+		fmt.Sprintf("layouts write the reference time %q, or name a format such as RFC3339", DateFormat))
+	return pe
+}
+
+// javaLike matches the runs of pattern letters that are common in Java and ICU patterns
+var javaLike = regexp.MustCompile(`yy|MM|dd|HH|hh|mm|ss|SS|EEE`)
+
+// LooksLikeJava reports whether format looks like a Java DateTimeFormatter or ICU pattern
+func LooksLikeJava(format string) bool {
+	return javaLike.MatchString(format)
+}
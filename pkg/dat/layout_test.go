@@ -0,0 +1,50 @@
+package dat
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLayout(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		suggestions []string
+	}{
+		{"layout", "2006-01-02", nil},
+		{"named format", "rfc3339", nil},
+		{"only a meridiem", "PM", nil},
+		{"no elements", "hello", []string{
+			`layouts write the reference time "01/02/2006 15:04:05 -0700", or name a format such as RFC3339`}},
+		{"java pattern", "yyyy-MM-dd HH:mm", []string{
+			`looks like a java pattern, the layout is "2006-01-02 15:04"`,
+			`layouts write the reference time "01/02/2006 15:04:05 -0700", or name a format such as RFC3339`}},
+		{"untranslatable java pattern", "yyyy-MM-dd QQQ", []string{
+			`looks like a java pattern, but pattern letter 'Q' has no layout element`,
+			`layouts write the reference time "01/02/2006 15:04:05 -0700", or name a format such as RFC3339`}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateLayout(test.format)
+			if test.suggestions == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidLayout)
+			var pe *ParseError
+			if assert.True(t, errors.As(err, &pe)) {
+				assert.Equal(t, test.suggestions, pe.Suggestions)
+			}
+		})
+	}
+}
+
+func TestFormatOutput_ReferenceYear(t *testing.T) {
+	// formatting a time in 2006 with 2006 gives the layout back, it is not a fallback
+	tm := time.Date(2006, time.March, 4, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2006", FormatOutput(tm, "2006"))
+	assert.Equal(t, tm.Format(DateFormat), FormatOutput(tm, "hello"))
+}
@@ -0,0 +1,289 @@
+package dat
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ntpToUnix is the number of seconds between the ntp epoch 1900-01-01 and the unix epoch
+const ntpToUnix = 2208988800
+
+// gpsTAIOffset is TAI - GPS in seconds, fixed since the GPS epoch
+const gpsTAIOffset = 19
+
+// LeapSecondWindow is how close to a leap second an instant is considered near it
+const LeapSecondWindow = time.Minute
+
+//go:embed leap-seconds.list
+var embeddedLeapSeconds string
+
+// leapSecond is the offset between TAI and UTC from an instant onward
+type leapSecond struct {
+	// unix is the first second the offset applies
+	unix int64
+	// offset is TAI - UTC in seconds
+	offset int
+}
+
+// LeapSecondTable is a list of leap seconds in the IERS leap-seconds.list format.
+type LeapSecondTable struct {
+	entries []leapSecond
+	// Updated is when the list was last updated
+	Updated time.Time
+	// Expires is when the list stops being authoritative, offsets after it may be wrong.
+	Expires time.Time
+}
+
+var leapSeconds atomic.Pointer[LeapSecondTable]
+
+func init() {
+	table, err := ParseLeapSeconds(strings.NewReader(embeddedLeapSeconds))
+	if err != nil {
+		panic(err)
+	}
+	leapSeconds.Store(table)
+}
+
+// LeapSeconds returns the leap second table in use.
+func LeapSeconds() *LeapSecondTable {
+	return leapSeconds.Load()
+}
+
+// SetLeapSeconds replaces the leap second table in use.
+func SetLeapSeconds(table *LeapSecondTable) {
+	leapSeconds.Store(table)
+}
+
+// LoadLeapSeconds reads a leap-seconds.list file and uses it when it expires later
+// than the table in use, reporting whether it was used.
+func LoadLeapSeconds(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	table, err := ParseLeapSeconds(f)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", path, err)
+	}
+	if !table.Expires.After(LeapSeconds().Expires) {
+		return false, nil
+	}
+	SetLeapSeconds(table)
+	return true, nil
+}
+
+// ParseLeapSeconds reads a leap second table in the IERS leap-seconds.list format.
+func ParseLeapSeconds(r io.Reader) (*LeapSecondTable, error) {
+	table := &LeapSecondTable{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(text, "#$"), strings.HasPrefix(text, "#@"):
+			ntp, err := strconv.ParseInt(strings.TrimSpace(text[2:]), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid timestamp: %w", line, err)
+			}
+			if text[1] == '$' {
+				table.Updated = time.Unix(ntp-ntpToUnix, 0).UTC()
+			} else {
+				table.Expires = time.Unix(ntp-ntpToUnix, 0).UTC()
+			}
+			continue
+		case text == "", strings.HasPrefix(text, "#"):
+			continue
+		}
+
+		fields := strings.Fields(strings.SplitN(text, "#", 2)[0])
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected ntp time and offset", line)
+		}
+		ntp, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid ntp time: %w", line, err)
+		}
+		offset, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid offset: %w", line, err)
+		}
+		table.entries = append(table.entries, leapSecond{unix: ntp - ntpToUnix, offset: offset})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(table.entries) == 0 {
+		return nil, errors.New("no leap seconds found")
+	}
+	sort.Slice(table.entries, func(i, j int) bool {
+		return table.entries[i].unix < table.entries[j].unix
+	})
+	return table, nil
+}
+
+// TAIOffset returns TAI - UTC in seconds at the given instant.
+// Before 1972 the offset was not a whole number of seconds, the 1972 offset of 10s is used.
+func (t *LeapSecondTable) TAIOffset(tm time.Time) int {
+	unix := tm.Unix()
+	i := sort.Search(len(t.entries), func(i int) bool {
+		return t.entries[i].unix > unix
+	})
+	if i == 0 {
+		return t.entries[0].offset
+	}
+	return t.entries[i-1].offset
+}
+
+// Nearest returns the leap second nearest to tm, as the utc instant following it,
+// and whether there is one.
+func (t *LeapSecondTable) Nearest(tm time.Time) (time.Time, bool) {
+	unix := tm.Unix()
+	i := sort.Search(len(t.entries), func(i int) bool {
+		return t.entries[i].unix > unix
+	})
+	var candidates []leapSecond
+	// the first entry sets the initial offset, it is not a leap second
+	if i > 1 {
+		candidates = append(candidates, t.entries[i-1])
+	}
+	if i > 0 && i < len(t.entries) {
+		candidates = append(candidates, t.entries[i])
+	}
+	if len(candidates) == 0 {
+		return time.Time{}, false
+	}
+	nearest := candidates[0]
+	for _, c := range candidates[1:] {
+		if abs64(c.unix-unix) < abs64(nearest.unix-unix) {
+			nearest = c
+		}
+	}
+	return time.Unix(nearest.unix, 0).UTC(), true
+}
+
+func abs64(i int64) int64 {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+// TAIOffset returns TAI - UTC in seconds at the given instant using the table in use.
+func TAIOffset(tm time.Time) int {
+	return LeapSeconds().TAIOffset(tm)
+}
+
+// GPSOffset returns GPS - UTC in seconds at the given instant using the table in use.
+func GPSOffset(tm time.Time) int {
+	return TAIOffset(tm) - gpsTAIOffset
+}
+
+// Scale is a time scale, the way a clock counts seconds
+type Scale string
+
+const (
+	// ScaleUTC coordinated universal time, what time.Time uses, ignoring leap seconds
+	ScaleUTC Scale = "utc"
+	// ScaleTAI international atomic time, ahead of UTC by every leap second
+	ScaleTAI Scale = "tai"
+	// ScaleGPS GPS time, TAI minus 19 seconds
+	ScaleGPS Scale = "gps"
+)
+
+// Scales are the supported time scales
+var Scales = []Scale{ScaleUTC, ScaleTAI, ScaleGPS}
+
+// ParseScale returns the scale with the given name, case insensitive.
+func ParseScale(name string) (Scale, error) {
+	lower := Scale(strings.ToLower(strings.TrimSpace(name)))
+	if lower == "" {
+		return ScaleUTC, nil
+	}
+	for _, s := range Scales {
+		if s == lower {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("unknown time scale %q", name)
+}
+
+// offset returns the scale - UTC in seconds at the utc instant
+func (s Scale) offset(utc time.Time) time.Duration {
+	switch s {
+	case ScaleTAI:
+		return time.Duration(TAIOffset(utc)) * time.Second
+	case ScaleGPS:
+		return time.Duration(GPSOffset(utc)) * time.Second
+	}
+	return 0
+}
+
+// ToScale converts the utc instant to a reading of a clock in the scale.
+func ToScale(utc time.Time, s Scale) time.Time {
+	return utc.Add(s.offset(utc))
+}
+
+// FromScale converts a reading of a clock in the scale to the utc instant.
+// Readings inside an inserted leap second have no utc instant, they are mapped
+// to the second before it and reported as on the leap second.
+func FromScale(reading time.Time, s Scale) (time.Time, bool) {
+	guess := reading.Add(-s.offset(reading))
+	for i := 0; i < 2; i++ {
+		off := s.offset(guess)
+		candidate := reading.Add(-off)
+		if s.offset(candidate) == off {
+			return candidate, false
+		}
+		if candidate.Before(guess) {
+			return candidate, true
+		}
+		guess = candidate
+	}
+	return guess, true
+}
+
+// LeapSecondNote describes the leap second near an instant
+type LeapSecondNote struct {
+	// Leap is the utc instant following the inserted second
+	Leap time.Time `json:"leap"`
+	// Offset is the time from the end of the inserted second to the instant
+	Offset time.Duration `json:"offset"`
+	// On reports the instant was read inside the inserted second
+	On bool `json:"on"`
+}
+
+// String implements fmt.Stringer
+func (n LeapSecondNote) String() string {
+	label := n.Leap.Add(-time.Second).Format("2006-01-02T15:04:") + "60Z"
+	switch {
+	case n.On:
+		return "on leap second " + label
+	case n.Offset < 0:
+		return fmt.Sprintf("%s before leap second %s", -n.Offset, label)
+	default:
+		return fmt.Sprintf("%s after leap second %s", n.Offset, label)
+	}
+}
+
+// NearLeapSecond returns a note when tm is within LeapSecondWindow of a leap second.
+func NearLeapSecond(tm time.Time) *LeapSecondNote {
+	leap, ok := LeapSeconds().Nearest(tm)
+	if !ok {
+		return nil
+	}
+	offset := tm.Sub(leap)
+	if offset < -LeapSecondWindow || offset > LeapSecondWindow {
+		return nil
+	}
+	return &LeapSecondNote{Leap: leap, Offset: offset}
+}
@@ -0,0 +1,164 @@
+package dat
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeapSecondTable(t *testing.T) {
+	table := LeapSeconds()
+	assert.Equal(t, time.Date(2026, 12, 28, 0, 0, 0, 0, time.UTC), table.Expires)
+	assert.True(t, table.Updated.Before(table.Expires))
+	for i, ls := range table.entries {
+		tm := time.Unix(ls.unix, 0).UTC()
+		assert.Equal(t, 1, tm.Day(), tm.String())
+		assert.Contains(t, []time.Month{time.January, time.July}, tm.Month(), tm.String())
+		assert.Equal(t, 10+i, ls.offset)
+	}
+}
+
+func TestParseLeapSeconds(t *testing.T) {
+	tests := []struct {
+		name    string
+		list    string
+		entries []leapSecond
+		expires time.Time
+		err     string
+	}{
+		{"valid", "#$ 3676924800\n#@ 4000000000\n2272060800 10 # 1 Jan 1972\n\n2287785600\t11\n",
+			[]leapSecond{{63072000, 10}, {78796800, 11}}, time.Unix(4000000000-ntpToUnix, 0).UTC(), ""},
+		{"sorted", "2287785600 11\n2272060800 10\n",
+			[]leapSecond{{63072000, 10}, {78796800, 11}}, time.Time{}, ""},
+		{"empty", "# nothing\n", nil, time.Time{}, "no leap seconds found"},
+		{"bad offset", "2272060800 ten\n", nil, time.Time{}, "line 1: invalid offset"},
+		{"bad fields", "2272060800\n", nil, time.Time{}, "line 1: expected ntp time and offset"},
+		{"bad expiry", "#@ soon\n", nil, time.Time{}, "line 1: invalid timestamp"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table, err := ParseLeapSeconds(strings.NewReader(test.list))
+			if test.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), test.err)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.entries, table.entries)
+			assert.Equal(t, test.expires, table.Expires)
+		})
+	}
+}
+
+func TestLoadLeapSeconds(t *testing.T) {
+	orig := LeapSeconds()
+	defer SetLeapSeconds(orig)
+
+	dir := t.TempDir()
+	older := filepath.Join(dir, "older.list")
+	require.NoError(t, os.WriteFile(older, []byte("#@ 3000000000\n2272060800 10\n"), 0o600))
+	used, err := LoadLeapSeconds(older)
+	assert.NoError(t, err)
+	assert.False(t, used)
+	assert.Same(t, orig, LeapSeconds())
+
+	// a list with a leap second at the end of 2030
+	newer := filepath.Join(dir, "newer.list")
+	list := embeddedLeapSeconds + "4133980800 38 # 1 Jan 2031\n"
+	list = regexp.MustCompile(`#@\s+\d+`).ReplaceAllString(list, "#@\t4200000000")
+	require.NoError(t, os.WriteFile(newer, []byte(list), 0o600))
+	used, err = LoadLeapSeconds(newer)
+	assert.NoError(t, err)
+	assert.True(t, used)
+	assert.Equal(t, 38, TAIOffset(time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	_, err = LoadLeapSeconds(filepath.Join(dir, "missing.list"))
+	assert.Error(t, err)
+}
+
+func TestTAIOffset(t *testing.T) {
+	assert.Equal(t, 10, TAIOffset(time.Unix(0, 0)))
+	assert.Equal(t, 36, TAIOffset(time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC)))
+	assert.Equal(t, 37, TAIOffset(time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 18, GPSOffset(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestParseScale(t *testing.T) {
+	for _, s := range Scales {
+		got, err := ParseScale(strings.ToUpper(string(s)))
+		assert.NoError(t, err)
+		assert.Equal(t, s, got)
+	}
+	got, err := ParseScale("")
+	assert.NoError(t, err)
+	assert.Equal(t, ScaleUTC, got)
+	_, err = ParseScale("tt")
+	assert.EqualError(t, err, `unknown time scale "tt"`)
+}
+
+func TestScales(t *testing.T) {
+	// the leap second inserted at the end of 2016
+	leap := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		reading time.Time
+		scale   Scale
+		utc     time.Time
+		on      bool
+	}{
+		{"utc", leap, ScaleUTC, leap, false},
+		{"tai before", leap.Add(35 * time.Second), ScaleTAI, leap.Add(-time.Second), false},
+		{"tai on", leap.Add(36*time.Second + 500*time.Millisecond), ScaleTAI, leap.Add(-500 * time.Millisecond), true},
+		{"tai after", leap.Add(37 * time.Second), ScaleTAI, leap, false},
+		{"gps on", leap.Add(17 * time.Second), ScaleGPS, leap.Add(-time.Second), true},
+		{"gps after", leap.Add(18 * time.Second), ScaleGPS, leap, false},
+		{"gps 2020", time.Date(2020, 1, 1, 0, 0, 18, 0, time.UTC), ScaleGPS, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			utc, on := FromScale(test.reading, test.scale)
+			assert.Equal(t, test.utc, utc.UTC())
+			assert.Equal(t, test.on, on)
+			if !on {
+				assert.Equal(t, test.reading, ToScale(utc, test.scale).UTC())
+			}
+		})
+	}
+}
+
+func TestNearLeapSecond(t *testing.T) {
+	leap := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		tm   time.Time
+		want string
+	}{
+		{"before", leap.Add(-2 * time.Second), "2s before leap second 2016-12-31T23:59:60Z"},
+		{"after", leap.Add(30 * time.Second), "30s after leap second 2016-12-31T23:59:60Z"},
+		{"far", leap.Add(time.Hour), ""},
+		{"first entry", time.Date(1972, 1, 1, 0, 0, 0, 0, time.UTC), ""},
+		{"1972", time.Date(1972, 7, 1, 0, 0, 1, 0, time.UTC), "1s after leap second 1972-06-30T23:59:60Z"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			note := NearLeapSecond(test.tm)
+			if test.want == "" {
+				assert.Nil(t, note)
+				return
+			}
+			if assert.NotNil(t, note) {
+				assert.Equal(t, test.want, note.String())
+			}
+		})
+	}
+
+	on := LeapSecondNote{Leap: leap, Offset: -500 * time.Millisecond, On: true}
+	assert.Equal(t, "on leap second 2016-12-31T23:59:60Z", on.String())
+}
@@ -0,0 +1,375 @@
+package dat
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed locales.json
+var embeddedLocales []byte
+
+// Locale is the month and weekday names and common date orderings of a language.
+// The orderings are layouts, written with the english names of the reference time.
+type Locale struct {
+	// Tag is the language code, such as fr
+	Tag  string `json:"-"`
+	Name string `json:"name"`
+	// Months are the month names, January first
+	Months [12]string `json:"months"`
+	// GenitiveMonths are the month names used with a day of the month, when the language inflects them
+	GenitiveMonths [12]string `json:"genitiveMonths"`
+	ShortMonths    [12]string `json:"shortMonths"`
+	// Days are the weekday names, Sunday first
+	Days      [7]string `json:"days"`
+	ShortDays [7]string `json:"shortDays"`
+	AM        string    `json:"am"`
+	PM        string    `json:"pm"`
+	// Numeric, Medium, Long and Full are the date orderings, any may be empty
+	Numeric string `json:"numeric"`
+	Medium  string `json:"medium"`
+	Long    string `json:"long"`
+	Full    string `json:"full"`
+
+	// names are the english names of the localized names, lower case
+	names map[string][]string
+}
+
+// localeAliases are language codes that share the data of another
+var localeAliases = map[string]string{
+	"no": "nb",
+	"nn": "nb",
+	"in": "id",
+}
+
+var locales map[string]*Locale
+
+func init() {
+	if err := json.Unmarshal(embeddedLocales, &locales); err != nil {
+		panic(err)
+	}
+	for tag, l := range locales {
+		l.Tag = tag
+		if l.AM == "" {
+			l.AM, l.PM = "AM", "PM"
+		}
+		l.names = l.englishNames()
+	}
+}
+
+// Locales returns the tags of the embedded locales.
+func Locales() []string {
+	tags := make([]string, 0, len(locales))
+	for tag := range locales {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// LookupLocale returns the locale of a POSIX or BCP 47 locale name such as fr_FR.UTF-8 or de-AT,
+// by its language. C and POSIX are the default, nil, locale.
+func LookupLocale(name string) (*Locale, error) {
+	lang := strings.ToLower(strings.TrimSpace(name))
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	if lang == "" || lang == "c" || lang == "posix" {
+		return nil, nil
+	}
+	if alias, ok := localeAliases[lang]; ok {
+		lang = alias
+	}
+	if l, ok := locales[lang]; ok {
+		return l, nil
+	}
+	return nil, fmt.Errorf("unknown locale %q, expected one of %s", name, strings.Join(Locales(), ", "))
+}
+
+// DateLayout is the default output layout of the locale, its numeric date ordering with
+// the time and offset of DateFormat.
+func (l *Locale) DateLayout() string {
+	if l == nil {
+		return DateFormat
+	}
+	return l.Numeric + " 15:04:05 -0700"
+}
+
+// formats are the date orderings of the locale, alone and with a time, named by their layout.
+// The full ordering is also read with abbreviated names, and orderings that end in a word after
+// the year, such as the г. of russian, are also read without it.
+func (l *Locale) formats() []namedFormat {
+	if l == nil {
+		return nil
+	}
+	dates := []string{l.Numeric, l.Medium, l.Long, l.Full}
+	if l.Full != "" {
+		dates = append(dates, strings.NewReplacer("Monday", "Mon", "January", "Jan").Replace(l.Full))
+	}
+	for _, date := range dates {
+		if trimmed := withoutYearSuffix(date); trimmed != date {
+			dates = append(dates, trimmed)
+		}
+	}
+	var formats []namedFormat
+	for _, date := range dates {
+		if date == "" {
+			continue
+		}
+		for _, layout := range []string{date, date + " 15:04", date + " 15:04:05", date + " 15:04:05 -0700"} {
+			formats = append(formats, namedFormat{layout, layout})
+		}
+	}
+	return formats
+}
+
+// withoutYearSuffix is the layout without the word written after the year, or the layout itself
+// when the year is followed by digits or names
+func withoutYearSuffix(layout string) string {
+	i := strings.LastIndex(layout, "2006")
+	if i < 0 {
+		return layout
+	}
+	suffix := layout[i+len("2006"):]
+	if strings.TrimSpace(suffix) == "" || strings.ContainsAny(suffix, "0123456789") {
+		return layout
+	}
+	if j, _ := nextNameElement(suffix); j >= 0 {
+		return layout
+	}
+	return layout[:i+len("2006")]
+}
+
+// englishNames maps the lower case localized names to the english names a layout parses
+func (l *Locale) englishNames() map[string][]string {
+	names := map[string][]string{}
+	add := func(name string, english ...string) {
+		key := strings.ToLower(strings.TrimSuffix(name, "."))
+		if key == "" {
+			return
+		}
+		for _, e := range english {
+			if !containsString(names[key], e) {
+				names[key] = append(names[key], e)
+			}
+		}
+	}
+	for i := range l.Months {
+		month := time.Month(i + 1)
+		add(l.Months[i], month.String())
+		add(l.GenitiveMonths[i], month.String())
+		add(l.ShortMonths[i], month.String()[:3])
+	}
+	for i := range l.Days {
+		day := time.Weekday(i)
+		add(l.Days[i], day.String())
+		add(l.ShortDays[i], day.String()[:3])
+	}
+	add(l.AM, "AM", "am")
+	add(l.PM, "PM", "pm")
+	return names
+}
+
+// maxLocaleCandidates bounds the readings of input with names that could be translated several ways
+const maxLocaleCandidates = 64
+
+// candidates are the readings of str with its localized names translated to english, the
+// fully translated readings first and str itself last. Names that are also literal text in
+// a layout, or that name both a month and a weekday, are read every way.
+func (l *Locale) candidates(str string) []string {
+	if l == nil {
+		return []string{str}
+	}
+	candidates := []string{""}
+	rest := str
+	for rest != "" {
+		start, end, ok := nextWord(rest)
+		if !ok {
+			break
+		}
+		word := rest[start:end]
+		english := l.names[strings.ToLower(word)]
+		if len(english) == 0 {
+			for i := range candidates {
+				candidates[i] += rest[:end]
+			}
+			rest = rest[end:]
+			continue
+		}
+		// an abbreviation written with its period is read without it
+		original := word
+		if strings.HasPrefix(rest[end:], ".") {
+			original += "."
+			end++
+		}
+		readings := append([]string{}, english...)
+		if !containsString(readings, original) {
+			readings = append(readings, original)
+		}
+		var next []string
+		for _, c := range candidates {
+			for _, e := range readings {
+				if len(next) < maxLocaleCandidates {
+					next = append(next, c+rest[:start]+e)
+				}
+			}
+		}
+		candidates = next
+		rest = rest[end:]
+	}
+	for i := range candidates {
+		candidates[i] += rest
+	}
+	if candidates[len(candidates)-1] != str {
+		candidates = append(candidates, str)
+	}
+	return candidates
+}
+
+// nextWord finds the next run of letters in s, with the - and . between letters of names such
+// as segunda-feira or π.μ
+func nextWord(s string) (int, int, bool) {
+	start := strings.IndexFunc(s, isNameRune)
+	if start < 0 {
+		return 0, 0, false
+	}
+	end := start
+	for end < len(s) {
+		r, size := utf8.DecodeRuneInString(s[end:])
+		if isNameRune(r) {
+			end += size
+			continue
+		}
+		if r == '-' || r == '.' {
+			if next, _ := utf8.DecodeRuneInString(s[end+size:]); isNameRune(next) {
+				end += size
+				continue
+			}
+		}
+		break
+	}
+	return start, end, true
+}
+
+// isNameRune reports whether r can be part of a name, marks included for scripts such as devanagari
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r)
+}
+
+// parseLocale parses str with the layout, reading localized names of the locale.
+// The error is that of str itself.
+func parseLocale(layout, str string, l *Locale) (time.Time, error) {
+	candidates := l.candidates(str)
+	for _, c := range candidates[:len(candidates)-1] {
+		if tm, err := time.Parse(layout, c); err == nil {
+			return tm, nil
+		}
+	}
+	return time.Parse(layout, str)
+}
+
+// FormatLocale formats tm like FormatOutput, with the month and weekday names of the locale.
+// Formats without layout elements fall back to the DateLayout of the locale.
+func FormatLocale(tm time.Time, format string, l *Locale) string {
+	if l == nil {
+		return FormatOutput(tm, format)
+	}
+	layout, fallback := outputLayout(format)
+	if fallback {
+		layout = l.DateLayout()
+	}
+
+	var b strings.Builder
+	genitive := l.GenitiveMonths[0] != "" && hasDay(layout)
+	for layout != "" {
+		i, name := nextNameElement(layout)
+		if i < 0 {
+			b.WriteString(tm.Format(layout))
+			break
+		}
+		b.WriteString(tm.Format(layout[:i]))
+		switch name {
+		case "January":
+			if genitive {
+				b.WriteString(l.GenitiveMonths[tm.Month()-1])
+			} else {
+				b.WriteString(l.Months[tm.Month()-1])
+			}
+		case "Jan":
+			b.WriteString(l.ShortMonths[tm.Month()-1])
+		case "Monday":
+			b.WriteString(l.Days[tm.Weekday()])
+		case "Mon":
+			b.WriteString(l.ShortDays[tm.Weekday()])
+		case "PM", "pm":
+			meridiem := l.AM
+			if tm.Hour() >= 12 {
+				meridiem = l.PM
+			}
+			if name == "pm" {
+				meridiem = strings.ToLower(meridiem)
+			}
+			b.WriteString(meridiem)
+		}
+		layout = layout[i+len(name):]
+	}
+	return b.String()
+}
+
+// nextNameElement finds the next layout element written as a name, January, Jan, Monday, Mon,
+// PM or pm, read the way time.Format reads them
+func nextNameElement(layout string) (int, string) {
+	for i := 0; i < len(layout); i++ {
+		rest := layout[i:]
+		switch {
+		case strings.HasPrefix(rest, "January"):
+			return i, "January"
+		case strings.HasPrefix(rest, "Jan") && !startsWithLower(rest[3:]):
+			return i, "Jan"
+		case strings.HasPrefix(rest, "Monday"):
+			return i, "Monday"
+		case strings.HasPrefix(rest, "Mon") && !startsWithLower(rest[3:]):
+			return i, "Mon"
+		case strings.HasPrefix(rest, "MST"):
+			i += 2
+		case strings.HasPrefix(rest, "PM"):
+			return i, "PM"
+		case strings.HasPrefix(rest, "pm"):
+			return i, "pm"
+		}
+	}
+	return -1, ""
+}
+
+// startsWithLower reports whether s starts with a lower case ascii letter
+func startsWithLower(s string) bool {
+	return s != "" && s[0] >= 'a' && s[0] <= 'z'
+}
+
+// hasDay reports whether the layout writes the day of the month, which inflects month names
+func hasDay(layout string) bool {
+	for layout != "" {
+		i, name := nextNameElement(layout)
+		if i < 0 {
+			break
+		}
+		layout = layout[:i] + "|" + layout[i+len(name):]
+	}
+	day := time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC)
+	return day.Format(layout) != day.AddDate(0, 0, 1).Format(layout)
+}
+
+// containsString reports whether s is in list
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
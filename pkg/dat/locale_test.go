@@ -0,0 +1,142 @@
+package dat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupLocale(t *testing.T) {
+	tests := []struct {
+		name  string
+		want  string
+		error bool
+	}{
+		{"", "", false},
+		{"C", "", false},
+		{"POSIX", "", false},
+		{"fr_FR.UTF-8", "fr", false},
+		{"de-AT", "de", false},
+		{"ja_JP", "ja", false},
+		{"no_NO", "nb", false},
+		{"sr@latin", "", true},
+		{"xx_YY", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := LookupLocale(test.name)
+			if test.error {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if test.want == "" {
+				assert.Nil(t, got)
+				return
+			}
+			if assert.NotNil(t, got) {
+				assert.Equal(t, test.want, got.Tag)
+			}
+		})
+	}
+	assert.Contains(t, Locales(), "de")
+	assert.Contains(t, Locales(), "zh")
+}
+
+func TestFormatLocale(t *testing.T) {
+	tm := time.Date(2023, time.November, 14, 22, 13, 20, 0, time.UTC)
+	tests := []struct {
+		locale string
+		format string
+		want   string
+	}{
+		{"fr", "2 January 2006", "14 novembre 2023"},
+		{"fr", "", "14/11/2023 22:13:20 +0000"},
+		{"fr", "Mon 2 Jan", "mar. 14 nov."},
+		{"de", "Mon, 2. Jan", "Di, 14. Nov"},
+		{"de", "Monday MST", "Dienstag UTC"},
+		{"pl", "2 January 2006", "14 listopada 2023"},
+		{"pl", "January 2006", "listopad 2023"},
+		{"ru", "long", "14.11.2023 22:13:20 +0000"},
+		{"ja", "2006年1月2日 Monday 3:04 PM", "2023年11月14日 火曜日 10:13 午後"},
+		{"el", "3:04 pm", "10:13 μ.μ."},
+		{"es", "rfc1123", "mar, 14 nov 2023 22:13:20 UTC"},
+		{"en", "", "11/14/2023 22:13:20 +0000"},
+	}
+	for _, test := range tests {
+		t.Run(test.locale+" "+test.format, func(t *testing.T) {
+			l, err := LookupLocale(test.locale)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, FormatLocale(tm, test.format, l))
+		})
+	}
+	assert.Equal(t, FormatOutput(tm, "Jan 2"), FormatLocale(tm, "Jan 2", nil))
+}
+
+func TestParseTimeLocale(t *testing.T) {
+	tm := time.Date(2023, time.November, 14, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		locale string
+		input  string
+		want   time.Time
+		layout string
+	}{
+		{"fr", "14 novembre 2023", tm, "2 January 2006"},
+		{"fr", "mardi 14 Novembre 2023 22:13", tm.Add(22*time.Hour + 13*time.Minute), "Monday 2 January 2006 15:04"},
+		{"fr", "14 nov. 2023", tm, "2 Jan 2006"},
+		{"fr", "14/11/2023", tm, "02/01/2006"},
+		{"de", "Di, 14 Nov 2023 22:13:20 UTC", tm.Add(22*time.Hour + 13*time.Minute + 20*time.Second), time.RFC1123},
+		{"es", "mar, 14 mar 2023 10:00:00 UTC", time.Date(2023, time.March, 14, 10, 0, 0, 0, time.UTC), time.RFC1123},
+		{"pt", "terça-feira, 14 de novembro de 2023", tm, "Monday, 2 de January de 2006"},
+		{"ru", "14 ноября 2023 г.", tm, "2 January 2006 г."},
+		{"ru", "14 ноября 2023", tm, "2 January 2006"},
+		{"uk", "14 листопада 2023", tm, "2 January 2006"},
+		{"de", "Di, 14. Nov 2023", tm, "Mon, 2. Jan 2006"},
+		{"fr", "mar. 14 nov. 2023 10:13", tm.Add(10*time.Hour + 13*time.Minute), "Mon 2 Jan 2006 15:04"},
+		{"ja", "2023年11月14日 火曜日", tm, "2006年1月2日 Monday"},
+		{"ko", "2023년 11월 14일", tm, "2006년 1월 2일"},
+	}
+	for _, test := range tests {
+		t.Run(test.locale+" "+test.input, func(t *testing.T) {
+			l, err := LookupLocale(test.locale)
+			require.NoError(t, err)
+			got, layout, err := ParseTimeLocale(test.input, l)
+			assert.NoError(t, err)
+			assert.True(t, test.want.Equal(got), got)
+			assert.Equal(t, test.layout, layout)
+		})
+	}
+
+	el, err := LookupLocale("el")
+	require.NoError(t, err)
+	got, err := parseLocale("2 January 2006 3:04 PM", "14 Νοεμβρίου 2023 10:13 μ.μ.", el)
+	assert.NoError(t, err)
+	assert.True(t, tm.Add(22*time.Hour+13*time.Minute).Equal(got), got)
+
+	_, _, err = ParseTime("14 novembre 2023")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+	fr, err := LookupLocale("fr")
+	require.NoError(t, err)
+	_, _, err = ParseTimeLocale("14 brumaire 2023", fr)
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestLocale_RoundTrip(t *testing.T) {
+	// every ordering of every locale parses its own output, for every month and weekday
+	for _, tag := range Locales() {
+		l, err := LookupLocale(tag)
+		require.NoError(t, err)
+		for _, f := range l.formats() {
+			for month := 1; month <= 12; month++ {
+				tm := time.Date(2023, time.Month(month), month+10, 13, 14, 0, 0, time.UTC)
+				formatted := FormatLocale(tm, f.layout, l)
+				got, err := parseLocale(f.layout, formatted, l)
+				if assert.NoError(t, err, "%s %q", tag, formatted) {
+					assert.Equal(t, tm.Format(f.layout), got.Format(f.layout), "%s %q", tag, formatted)
+				}
+			}
+		}
+	}
+}
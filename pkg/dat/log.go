@@ -0,0 +1,46 @@
+package dat
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// logger traces the decisions made parsing input and building output, it discards
+// everything until SetLogger is called. It is swapped atomically so conversions may run
+// while it is replaced.
+var logger atomic.Pointer[slog.Logger]
+
+func init() {
+	SetLogger(nil)
+}
+
+// SetLogger sets the logger tracing how input is parsed and output built: the modes and
+// layouts tried, the zone, delta and snapping applied and any fallbacks. Decisions are logged
+// at info, fallbacks at warn and each candidate layout at debug. nil discards the trace.
+func SetLogger(l *slog.Logger) {
+	if l == nil {
+		l = slog.New(discardHandler{})
+	}
+	logger.Store(l)
+}
+
+// Logger returns the logger set by SetLogger
+func Logger() *slog.Logger {
+	return logger.Load()
+}
+
+// discardHandler is a slog.Handler that is never enabled
+type discardHandler struct{}
+
+// Enabled implements slog.Handler
+func (discardHandler) Enabled(context.Context, slog.Level) bool { return false }
+
+// Handle implements slog.Handler
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+
+// WithAttrs implements slog.Handler
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+// WithGroup implements slog.Handler
+func (h discardHandler) WithGroup(string) slog.Handler { return h }
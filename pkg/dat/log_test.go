@@ -0,0 +1,61 @@
+package dat
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	SetLogger(slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer SetLogger(nil)
+
+	_, _, err := ParseTime("2023-11-14T22:13:20Z")
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), `msg="layout did not match" format=ANSIC`)
+	assert.Contains(t, buf.String(), `msg="input matched a time format" input=2023-11-14T22:13:20Z format=RFC3339`)
+
+	buf.Reset()
+	NewConverter(WithZone("Nowhere"), WithDelta("1x"), WithFormat("hello")).At(time.Unix(1700000000, 0))
+	assert.Contains(t, buf.String(), `level=WARN msg="zone could not be loaded, it is not displayed" zone=Nowhere`)
+	assert.Contains(t, buf.String(), `level=WARN msg="delta is not a duration, ignoring it" delta=1x`)
+	assert.Contains(t, buf.String(), `level=WARN msg="format has no layout elements, using the default format" format=hello`)
+
+	buf.Reset()
+	SetLogger(nil)
+	AddDelta(time.Unix(0, 0), "1x")
+	assert.Empty(t, buf.String())
+}
+
+func TestSetLogger_Concurrent(t *testing.T) {
+	defer SetLogger(nil)
+
+	// the logger may be replaced while conversions run, go test -race checks the access
+	conv := NewConverter(WithZone("UTC"), WithDelta("1h"))
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, err := conv.Convert("1700000000")
+				assert.NoError(t, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+				SetLogger(nil)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.NotNil(t, Logger())
+}
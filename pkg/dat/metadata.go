@@ -0,0 +1,71 @@
+package dat
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Metadata describes the calendar position of a time in its location
+type Metadata struct {
+	Weekday   time.Weekday `json:"weekday"`
+	ISOYear   int          `json:"isoYear"`
+	ISOWeek   int          `json:"isoWeek"`
+	DayOfYear int          `json:"dayOfYear"`
+	Quarter   int          `json:"quarter"`
+	LeapYear  bool         `json:"leapYear"`
+	// DaysInMonth is the length of the month of the time
+	DaysInMonth int `json:"daysInMonth"`
+	// SecondsSinceMidnight is the time elapsed since the start of the day,
+	// which differs from the wall clock on days with a daylight saving change.
+	SecondsSinceMidnight int64 `json:"secondsSinceMidnight"`
+}
+
+// MarshalJSON implements json.Marshaler, writing the weekday by name
+func (m Metadata) MarshalJSON() ([]byte, error) {
+	type plain Metadata
+	return json.Marshal(struct {
+		Weekday string `json:"weekday"`
+		plain
+	}{m.Weekday.String(), plain(m)})
+}
+
+// TimeMetadata describes a time in each clock it is shown in, see WithMetadata.
+type TimeMetadata struct {
+	Unix      int64    `json:"unix"`
+	UnixMilli int64    `json:"unixMilli"`
+	Local     Metadata `json:"local"`
+	UTC       Metadata `json:"utc"`
+	// Zone describes the clock of the zone, when one is shown
+	Zone *Metadata `json:"zone,omitempty"`
+}
+
+// Describe returns the metadata of tm in its location.
+func Describe(tm time.Time) Metadata {
+	isoYear, isoWeek := tm.ISOWeek()
+	return Metadata{
+		Weekday:              tm.Weekday(),
+		ISOYear:              isoYear,
+		ISOWeek:              isoWeek,
+		DayOfYear:            tm.YearDay(),
+		Quarter:              (int(tm.Month())-1)/3 + 1,
+		LeapYear:             IsLeapYear(tm.Year()),
+		DaysInMonth:          daysIn(tm),
+		SecondsSinceMidnight: int64(tm.Sub(StartOf(tm, UnitDay)) / time.Second),
+	}
+}
+
+// IsLeapYear reports whether the year of the gregorian calendar has a february 29th
+func IsLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// String implements fmt.Stringer
+func (m Metadata) String() string {
+	leap := "not a leap year"
+	if m.LeapYear {
+		leap = "leap year"
+	}
+	return fmt.Sprintf("%s, week %d-W%02d-%d, day %d, Q%d, %d days in month, %s, %ds since midnight",
+		m.Weekday, m.ISOYear, m.ISOWeek, (int(m.Weekday)+6)%7+1, m.DayOfYear, m.Quarter, m.DaysInMonth, leap, m.SecondsSinceMidnight)
+}
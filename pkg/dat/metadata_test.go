@@ -0,0 +1,48 @@
+package dat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribe(t *testing.T) {
+	la, err := time.LoadLocation(tzLosAngeles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		tm   time.Time
+		want Metadata
+		text string
+	}{
+		{"tuesday", time.Unix(1700000000, 0).UTC(),
+			Metadata{Weekday: time.Tuesday, ISOYear: 2023, ISOWeek: 46, DayOfYear: 318, Quarter: 4, DaysInMonth: 30, SecondsSinceMidnight: 80000},
+			"Tuesday, week 2023-W46-2, day 318, Q4, 30 days in month, not a leap year, 80000s since midnight"},
+		{"iso year before the calendar year", time.Date(2021, 1, 3, 0, 0, 1, 0, time.UTC),
+			Metadata{Weekday: time.Sunday, ISOYear: 2020, ISOWeek: 53, DayOfYear: 3, Quarter: 1, DaysInMonth: 31, SecondsSinceMidnight: 1},
+			"Sunday, week 2020-W53-7, day 3, Q1, 31 days in month, not a leap year, 1s since midnight"},
+		{"leap day", time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC),
+			Metadata{Weekday: time.Thursday, ISOYear: 2024, ISOWeek: 9, DayOfYear: 60, Quarter: 1, LeapYear: true, DaysInMonth: 29, SecondsSinceMidnight: 43200},
+			"Thursday, week 2024-W09-4, day 60, Q1, 29 days in month, leap year, 43200s since midnight"},
+		// the clock skips 2am, so 3am is two hours after midnight
+		{"daylight saving", time.Date(2024, 3, 10, 3, 0, 0, 0, la),
+			Metadata{Weekday: time.Sunday, ISOYear: 2024, ISOWeek: 10, DayOfYear: 70, Quarter: 1, LeapYear: true, DaysInMonth: 31, SecondsSinceMidnight: 7200},
+			"Sunday, week 2024-W10-7, day 70, Q1, 31 days in month, leap year, 7200s since midnight"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Describe(test.tm)
+			assert.Equal(t, test.want, got)
+			assert.Equal(t, test.text, got.String())
+		})
+	}
+}
+
+func TestIsLeapYear(t *testing.T) {
+	for year, want := range map[int]bool{1900: false, 2000: true, 2023: false, 2024: true, 2100: false} {
+		assert.Equal(t, want, IsLeapYear(year), year)
+	}
+}
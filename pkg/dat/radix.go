@@ -0,0 +1,143 @@
+package dat
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// radixPrefixes are the integer prefixes detected on epoch input
+var radixPrefixes = map[string]int{
+	"0x": 16,
+	"0o": 8,
+	"0b": 2,
+}
+
+// ByteOrders are the supported byte orders of raw byte input by name
+var ByteOrders = map[string]binary.ByteOrder{
+	"big":    binary.BigEndian,
+	"little": binary.LittleEndian,
+}
+
+// ParseByteOrder returns the byte order with the given name, big or little.
+func ParseByteOrder(name string) (binary.ByteOrder, error) {
+	if order, ok := ByteOrders[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return order, nil
+	}
+	return nil, fmt.Errorf("unknown byte order %q", name)
+}
+
+// ValidRadix reports whether radix is 0, meaning detect it from the prefix, or between 2 and 36.
+func ValidRadix(radix int) error {
+	if radix != 0 && (radix < 2 || radix > 36) {
+		return fmt.Errorf("invalid base %d, must be between 2 and 36", radix)
+	}
+	return nil
+}
+
+// splitRadix splits the sign and prefix from an integer, returning the digits and their radix.
+// Without a prefix the radix is the given one, or 10 when it is 0.
+// A prefix that disagrees with the given radix is left in the digits.
+func splitRadix(s string, radix int) (sign, digits string, r int) {
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+	if len(s) > 2 {
+		if pr, ok := radixPrefixes[strings.ToLower(s[:2])]; ok && (radix == 0 || radix == pr) {
+			return sign, s[2:], pr
+		}
+	}
+	if radix == 0 {
+		radix = 10
+	}
+	return sign, s, radix
+}
+
+// ParseInteger reads a signed 64 bit integer in the given radix, 0 detects the radix
+// from a 0x, 0o or 0b prefix and otherwise reads decimal. Leading zeros are not octal.
+func ParseInteger(str string, radix int) (int64, error) {
+	if err := ValidRadix(radix); err != nil {
+		return 0, err
+	}
+	sign, digits, r := splitRadix(strings.TrimSpace(str), radix)
+	Logger().Debug("integer normalized", "input", str, "sign", sign, "digits", digits, "radix", r)
+	return strconv.ParseInt(sign+digits, r, 64)
+}
+
+// FormatInteger formats i in the radix, with a prefix for hex, octal and binary.
+func FormatInteger(i int64, radix int) string {
+	if radix == 0 || radix == 10 {
+		return strconv.FormatInt(i, 10)
+	}
+	sign := ""
+	u := uint64(i)
+	if i < 0 {
+		sign, u = "-", -u
+	}
+	prefix := ""
+	for p, pr := range radixPrefixes {
+		if pr == radix {
+			prefix = p
+		}
+	}
+	return sign + prefix + strconv.FormatUint(u, radix)
+}
+
+// ParseEpochRadix converts an epoch in the given radix to a time, see ParseInteger.
+func ParseEpochRadix(str string, radix int, milliseconds bool) (time.Time, error) {
+	if err := ValidRadix(radix); err != nil {
+		return time.Time{}, err
+	}
+	epoch, err := ParseInteger(str, radix)
+	if errors.Is(err, strconv.ErrRange) {
+		return time.Time{}, &ParseError{Input: str, Pos: -1, Err: ErrOutOfRange,
+			Suggestions: []string{"epochs must fit in a signed 64 bit integer"}}
+	} else if err != nil {
+		return time.Time{}, notAnEpochRadix(str, radix)
+	}
+	return epochTime(str, epoch, milliseconds)
+}
+
+// ParseEpochBytes converts raw bytes, written as hex, holding a 32 or 64 bit epoch to a time.
+// 32 bit fields are unsigned and 64 bit fields signed. Spaces, colons and a 0x prefix are ignored.
+func ParseEpochBytes(str string, order binary.ByteOrder, milliseconds bool) (time.Time, error) {
+	s := strings.TrimSpace(str)
+	if strings.HasPrefix(strings.ToLower(s), "0x") {
+		s = s[2:]
+	}
+	s = strings.NewReplacer(" ", "", ":", "").Replace(s)
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return time.Time{}, &ParseError{Input: str, Pos: -1, Err: ErrNotAnEpoch,
+			Suggestions: []string{"bytes are written as pairs of hex digits"}}
+	}
+
+	var epoch int64
+	switch len(b) {
+	case 4:
+		epoch = int64(order.Uint32(b))
+	case 8:
+		epoch = int64(order.Uint64(b))
+	default:
+		return time.Time{}, &ParseError{Input: str, Pos: -1, Err: ErrNotAnEpoch,
+			Suggestions: []string{fmt.Sprintf("got %d bytes, epochs are 4 or 8 bytes", len(b))}}
+	}
+	return epochTime(str, epoch, milliseconds)
+}
+
+// epochTime converts the epoch read from str to a time
+func epochTime(str string, epoch int64, milliseconds bool) (time.Time, error) {
+	if milliseconds {
+		return time.UnixMilli(epoch), nil
+	}
+
+	if epoch < MinUnix || epoch > MaxUnix {
+		return time.Time{}, &ParseError{Input: str, Pos: -1, Err: ErrOutOfRange,
+			Suggestions: []string{"epoch is beyond the range of supported times"}}
+	}
+	return time.Unix(epoch, 0), nil
+}
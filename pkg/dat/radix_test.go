@@ -0,0 +1,117 @@
+package dat
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInteger(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		radix int
+		want  int64
+		error bool
+	}{
+		{"decimal", "1700000000", 0, 1700000000, false},
+		{"leading zero is decimal", "0123", 0, 123, false},
+		{"hex prefix", "0x6553F100", 0, 1700000000, false},
+		{"octal prefix", "0o14524770400", 0, 1700000000, false},
+		{"binary prefix", "0b1100101010100111111000100000000", 0, 1700000000, false},
+		{"negative hex", "-0x10", 0, -16, false},
+		{"explicit hex", "6553f100", 16, 1700000000, false},
+		{"explicit hex with prefix", "0x6553f100", 16, 1700000000, false},
+		{"base 36", "S44WE8", 36, 1700000000, false},
+		{"prefix disagrees", "0x10", 8, 0, true},
+		{"hex without prefix", "6553f100", 0, 0, true},
+		{"invalid radix", "10", 37, 0, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseInteger(test.input, test.radix)
+			if test.error {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestFormatInteger(t *testing.T) {
+	assert.Equal(t, "1700000000", FormatInteger(1700000000, 0))
+	assert.Equal(t, "0x6553f100", FormatInteger(1700000000, 16))
+	assert.Equal(t, "0o14524770400", FormatInteger(1700000000, 8))
+	assert.Equal(t, "-0b11", FormatInteger(-3, 2))
+	assert.Equal(t, "s44we8", FormatInteger(1700000000, 36))
+	assert.Equal(t, "-0x8000000000000000", FormatInteger(-1<<63, 16))
+}
+
+func TestParseEpochRadix_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		radix int
+		err   error
+		pos   int
+	}{
+		{"bad hex digit", "0x6553g100", 0, ErrNotAnEpoch, 6},
+		{"bad explicit digit", " 12a", 8, ErrNotAnEpoch, 3},
+		{"missing digits", "0b", 2, ErrNotAnEpoch, 1},
+		{"overflow", "0xffffffffffffffffff", 0, ErrOutOfRange, -1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseEpochRadix(test.input, test.radix, false)
+			assert.ErrorIs(t, err, test.err)
+			var pe *ParseError
+			if assert.True(t, errors.As(err, &pe)) {
+				assert.Equal(t, test.pos, pe.Pos)
+			}
+		})
+	}
+}
+
+func TestParseEpochBytes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		order binary.ByteOrder
+		ms    bool
+		want  time.Time
+		error bool
+	}{
+		{"little endian 32", "00f15365", binary.LittleEndian, false, time.Unix(1700000000, 0), false},
+		{"big endian 32", "6553f100", binary.BigEndian, false, time.Unix(1700000000, 0), false},
+		{"unsigned 32", "ffffffff", binary.BigEndian, false, time.Unix(4294967295, 0), false},
+		{"separators", "0x00:f1:53:65", binary.LittleEndian, false, time.Unix(1700000000, 0), false},
+		{"little endian 64 ms", "00 9d e5 cf 8b 01 00 00", binary.LittleEndian, true, time.UnixMilli(1700000013568), false},
+		{"odd digits", "f15365", binary.BigEndian, false, time.Time{}, true},
+		{"wrong length", "f153", binary.BigEndian, false, time.Time{}, true},
+		{"not hex", "zzzzzzzz", binary.BigEndian, false, time.Time{}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseEpochBytes(test.input, test.order, test.ms)
+			if test.error {
+				assert.ErrorIs(t, err, ErrNotAnEpoch)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, test.want.Equal(got), got.String())
+		})
+	}
+}
+
+func TestParseByteOrder(t *testing.T) {
+	order, err := ParseByteOrder("Little")
+	assert.NoError(t, err)
+	assert.Equal(t, binary.LittleEndian, order)
+	_, err = ParseByteOrder("middle")
+	assert.EqualError(t, err, `unknown byte order "middle"`)
+}
@@ -0,0 +1,130 @@
+package dat
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+// The range of time.Time as unix seconds, beyond these the internal representation overflows.
+const (
+	MinUnix int64 = -9223372028715321600 // -292277022399-01-01T00:00:00Z
+	MaxUnix int64 = math.MaxInt64 - 62135596800
+)
+
+// the years considered plausible when guessing the unit of an epoch
+const (
+	plausibleMinYear = 1971
+	plausibleMaxYear = 2200
+)
+
+// Warning flags a result that is valid but suspicious.
+type Warning string
+
+const (
+	// WarnLooksLikeSeconds the value is implausible in its precision but plausible as seconds
+	WarnLooksLikeSeconds Warning = "value looks like seconds"
+	// WarnLooksLikeMilliseconds the value is implausible in its precision but plausible as milliseconds
+	WarnLooksLikeMilliseconds Warning = "value looks like milliseconds"
+	// WarnImplausible the value is far outside of any common range
+	WarnImplausible Warning = "value is implausibly far from the present"
+	// WarnEpochOverflow the time cannot be represented as an epoch in the precision
+	WarnEpochOverflow Warning = "time overflows the epoch precision"
+	// WarnLeapSecondsExpired the time is after the leap second table expires, its scale offset may be wrong
+	WarnLeapSecondsExpired Warning = "time is after the leap second table expires"
+)
+
+// looksLike maps a precision to its warning
+var looksLike = map[Precision]Warning{
+	Seconds:      WarnLooksLikeSeconds,
+	Milliseconds: WarnLooksLikeMilliseconds,
+}
+
+// unitsPerSecond of each precision
+var unitsPerSecond = map[Precision]int64{
+	Seconds:      1,
+	Milliseconds: 1e3,
+}
+
+// epochFits reports whether tm can be represented as an epoch in the precision.
+// Epochs are floored as time.Time.UnixMilli does, so a fraction of a unit still fits.
+func epochFits(tm time.Time, p Precision) bool {
+	units := unitsPerSecond[p]
+	epoch := new(big.Int).Mul(big.NewInt(tm.Unix()), big.NewInt(units))
+	epoch.Add(epoch, big.NewInt(int64(tm.Nanosecond())/(int64(time.Second)/units)))
+	return epoch.IsInt64()
+}
+
+func plausible(tm time.Time) bool {
+	year := tm.UTC().Year()
+	return year >= plausibleMinYear && year <= plausibleMaxYear
+}
+
+// GuessPrecision returns the precision an epoch is a plausible time in, seconds when
+// it is plausible in both or in neither.
+func GuessPrecision(epoch int64) Precision {
+	if !plausible(time.Unix(epoch, 0)) && plausible(time.UnixMilli(epoch)) {
+		return Milliseconds
+	}
+	return Seconds
+}
+
+// Check returns warnings for the given input time, which is the time parsed from an epoch
+// in the converter precision.
+func (c *Converter) Check(tm time.Time) []Warning {
+	var warnings []Warning
+	if !epochFits(tm, c.precision) {
+		warnings = append(warnings, WarnEpochOverflow)
+	}
+	if c.usesScales() && tm.After(LeapSeconds().Expires) {
+		warnings = append(warnings, WarnLeapSecondsExpired)
+	}
+	if c.timeFormats || c.base != BaseUnix || plausible(tm) {
+		return warnings
+	}
+
+	// the epoch value as it was given, read in each other precision
+	if epochFits(tm, c.precision) {
+		epoch := c.Epoch(tm)
+		for _, p := range []Precision{Seconds, Milliseconds} {
+			if p == c.precision {
+				continue
+			}
+			sec := epoch / unitsPerSecond[p]
+			if sec >= MinUnix && sec <= MaxUnix && plausible(time.Unix(sec, 0)) {
+				return append(warnings, looksLike[p])
+			}
+		}
+	}
+
+	if year := tm.UTC().Year(); year < 1 || year > 9999 {
+		warnings = append(warnings, WarnImplausible)
+	}
+	return warnings
+}
+
+// usesScales reports whether conversions depend on the leap second table
+func (c *Converter) usesScales() bool {
+	return c.scale != ScaleUTC || c.outputScale != ScaleUTC || c.base == BaseGPS || c.outputBase == BaseGPS
+}
+
+// checkBounds validates tm against the configured year bounds.
+func (c *Converter) checkBounds(input string, tm time.Time) error {
+	year := tm.UTC().Year()
+	var reason string
+	switch {
+	case c.hasMinYear && year < c.minYear:
+		reason = fmt.Sprintf("year %d is before the minimum year %d", year, c.minYear)
+	case c.hasMaxYear && year > c.maxYear:
+		reason = fmt.Sprintf("year %d is after the maximum year %d", year, c.maxYear)
+	default:
+		return nil
+	}
+
+	pe := &ParseError{Input: input, Pos: -1, Err: ErrOutOfRange, Suggestions: []string{reason}}
+	for _, w := range c.Check(tm) {
+		pe.Suggestions = append(pe.Suggestions, string(w))
+	}
+	return pe
+}
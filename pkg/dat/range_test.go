@@ -0,0 +1,111 @@
+package dat
+
+import (
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEpochTime_Range(t *testing.T) {
+	tests := []struct {
+		name         string
+		str          string
+		milliseconds bool
+		want         time.Time
+		err          error
+	}{
+		{"max unix", strconv.FormatInt(MaxUnix, 10), false, time.Unix(MaxUnix, 0), nil},
+		{"min unix", strconv.FormatInt(MinUnix, 10), false, time.Unix(MinUnix, 0), nil},
+		{"beyond max unix", strconv.FormatInt(MaxUnix+1, 10), false, time.Time{}, ErrOutOfRange},
+		{"beyond min unix", strconv.FormatInt(MinUnix-1, 10), false, time.Time{}, ErrOutOfRange},
+		{"max milliseconds", strconv.FormatInt(math.MaxInt64, 10), true, time.UnixMilli(math.MaxInt64), nil},
+		{"past year 2262 in milliseconds", "9300000000000000", true, time.Unix(9300000000000, 0), nil},
+		{"min milliseconds", strconv.FormatInt(math.MinInt64, 10), true, time.UnixMilli(math.MinInt64), nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseEpochTime(test.str, test.milliseconds)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, test.want.Equal(got))
+		})
+	}
+}
+
+func TestConverter_Check(t *testing.T) {
+	tests := []struct {
+		name      string
+		precision Precision
+		time      time.Time
+		want      []Warning
+	}{
+		{"plausible seconds", Seconds, time.Unix(1601167426, 0), nil},
+		{"plausible milliseconds", Milliseconds, time.Unix(1601167426, 0), nil},
+		{"milliseconds as seconds", Seconds, time.Unix(1601167426000, 0), []Warning{WarnLooksLikeMilliseconds}},
+		{"seconds as milliseconds", Milliseconds, time.UnixMilli(1601167426), []Warning{WarnLooksLikeSeconds}},
+		{"epoch zero", Seconds, time.Unix(0, 0), nil},
+		{"far future", Seconds, time.Unix(MaxUnix, 0), []Warning{WarnImplausible}},
+		{"milliseconds overflow", Milliseconds, time.Unix(MaxUnix, 0), []Warning{WarnEpochOverflow, WarnImplausible}},
+		{"min milliseconds", Milliseconds, time.UnixMilli(math.MinInt64), []Warning{WarnImplausible}},
+		{"max milliseconds", Milliseconds, time.UnixMilli(math.MaxInt64), []Warning{WarnImplausible}},
+		{"below min milliseconds", Milliseconds, time.UnixMilli(math.MinInt64).Add(-time.Millisecond), []Warning{WarnEpochOverflow, WarnImplausible}},
+		{"above max milliseconds", Milliseconds, time.UnixMilli(math.MaxInt64).Add(time.Millisecond), []Warning{WarnEpochOverflow, WarnImplausible}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := NewConverter(WithPrecision(test.precision)).Check(test.time)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestConverter_CheckLeapSecondsExpired(t *testing.T) {
+	expired := LeapSeconds().Expires.Add(time.Hour)
+	assert.Nil(t, NewConverter().Check(expired))
+	assert.Equal(t, []Warning{WarnLeapSecondsExpired}, NewConverter(WithScale(ScaleTAI)).Check(expired))
+	assert.Equal(t, []Warning{WarnLeapSecondsExpired}, NewConverter(WithOutputBase(BaseGPS)).Check(expired))
+	assert.Nil(t, NewConverter(WithOutputScale(ScaleGPS)).Check(time.Unix(1601167426, 0)))
+}
+
+func TestConverter_YearBounds(t *testing.T) {
+	conv := NewConverter(WithMinYear(1990), WithMaxYear(2100))
+	_, err := conv.Parse("1601167426")
+	assert.NoError(t, err)
+
+	_, err = conv.Parse("0")
+	var pe *ParseError
+	if assert.ErrorAs(t, err, &pe) {
+		assert.ErrorIs(t, err, ErrOutOfRange)
+		assert.Equal(t, []string{"year 1970 is before the minimum year 1990"}, pe.Suggestions)
+	}
+
+	_, err = conv.Parse("1601167426000")
+	if assert.ErrorAs(t, err, &pe) {
+		assert.ErrorIs(t, err, ErrOutOfRange)
+		assert.Equal(t, []string{"year 52708 is after the maximum year 2100", string(WarnLooksLikeMilliseconds)}, pe.Suggestions)
+	}
+}
+
+func TestGuessPrecision(t *testing.T) {
+	tests := []struct {
+		name  string
+		epoch int64
+		want  Precision
+	}{
+		{"seconds", 1601167426, Seconds},
+		{"milliseconds", 1601167426000, Milliseconds},
+		{"epoch zero", 0, Seconds},
+		{"implausible", math.MaxInt64, Seconds},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, GuessPrecision(test.epoch))
+		})
+	}
+}
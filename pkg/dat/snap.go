@@ -0,0 +1,153 @@
+package dat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Unit is a calendar or clock unit a time can be snapped to
+type Unit string
+
+const (
+	// UnitSecond the start of a second
+	UnitSecond Unit = "second"
+	// UnitMinute the start of a minute
+	UnitMinute Unit = "minute"
+	// UnitHour the start of an hour on the wall clock
+	UnitHour Unit = "hour"
+	// UnitDay midnight
+	UnitDay Unit = "day"
+	// UnitWeek midnight on the monday of the ISO week
+	UnitWeek Unit = "week"
+	// UnitMonth midnight on the first of the month
+	UnitMonth Unit = "month"
+	// UnitQuarter midnight on the first of january, april, july or october
+	UnitQuarter Unit = "quarter"
+	// UnitYear midnight on the first of january
+	UnitYear Unit = "year"
+)
+
+// Units are the supported units from the shortest to the longest
+var Units = []Unit{UnitSecond, UnitMinute, UnitHour, UnitDay, UnitWeek, UnitMonth, UnitQuarter, UnitYear}
+
+// unitAliases are alternate names for units
+var unitAliases = map[string]Unit{
+	"s":       UnitSecond,
+	"sec":     UnitSecond,
+	"m":       UnitMinute,
+	"min":     UnitMinute,
+	"h":       UnitHour,
+	"d":       UnitDay,
+	"w":       UnitWeek,
+	"isoweek": UnitWeek,
+	"mo":      UnitMonth,
+	"q":       UnitQuarter,
+	"y":       UnitYear,
+}
+
+// ParseUnit returns the unit with the given name or alias, case insensitive.
+func ParseUnit(name string) (Unit, error) {
+	lower := strings.ToLower(strings.TrimSpace(name))
+	if unit, ok := unitAliases[lower]; ok {
+		return unit, nil
+	}
+	for _, u := range Units {
+		if string(u) == lower {
+			return u, nil
+		}
+	}
+	return "", fmt.Errorf("unknown unit %q", name)
+}
+
+// clockUnits are the units shorter than a day
+var clockUnits = map[Unit]time.Duration{
+	UnitSecond: time.Second,
+	UnitMinute: time.Minute,
+	UnitHour:   time.Hour,
+}
+
+// StartOf returns the start of the unit containing tm, in tm's location.
+func StartOf(tm time.Time, unit Unit) time.Time {
+	if d, ok := clockUnits[unit]; ok {
+		return truncateWall(tm, d)
+	}
+
+	year, month, day := tm.Date()
+	loc := tm.Location()
+	switch unit {
+	case UnitWeek:
+		day -= (int(tm.Weekday()) + 6) % 7
+	case UnitMonth:
+		day = 1
+	case UnitQuarter:
+		month, day = month-(month-1)%3, 1
+	case UnitYear:
+		month, day = time.January, 1
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// truncateWall truncates the wall clock of tm to a multiple of d at the offset in effect,
+// keeping repeated hours apart
+func truncateWall(tm time.Time, d time.Duration) time.Time {
+	_, offset := tm.Zone()
+	shift := time.Duration(offset) * time.Second
+	return tm.Add(shift).Truncate(d).Add(-shift)
+}
+
+// next returns the start of the unit following the one starting at start
+func next(start time.Time, unit Unit) time.Time {
+	if d, ok := clockUnits[unit]; ok {
+		return start.Add(d)
+	}
+	switch unit {
+	case UnitWeek:
+		return start.AddDate(0, 0, 7)
+	case UnitMonth:
+		return start.AddDate(0, 1, 0)
+	case UnitQuarter:
+		return start.AddDate(0, 3, 0)
+	case UnitYear:
+		return start.AddDate(1, 0, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// EndOf returns the last nanosecond of the unit containing tm, in tm's location.
+func EndOf(tm time.Time, unit Unit) time.Time {
+	return next(StartOf(tm, unit), unit).Add(-time.Nanosecond)
+}
+
+// Round returns the start of the unit nearest to tm, halves round up.
+func Round(tm time.Time, unit Unit) time.Time {
+	start := StartOf(tm, unit)
+	end := next(start, unit)
+	if tm.Sub(start) >= end.Sub(tm) {
+		return end
+	}
+	return start
+}
+
+// SnapMode is how a time is snapped to a unit
+type SnapMode string
+
+const (
+	// SnapTruncate snaps to the start of the unit
+	SnapTruncate SnapMode = "truncate"
+	// SnapRound snaps to the nearest start of a unit
+	SnapRound SnapMode = "round"
+	// SnapEnd snaps to the last nanosecond of the unit
+	SnapEnd SnapMode = "end"
+)
+
+// Snap moves tm to a boundary of the unit in tm's location.
+func Snap(tm time.Time, mode SnapMode, unit Unit) time.Time {
+	switch mode {
+	case SnapRound:
+		return Round(tm, unit)
+	case SnapEnd:
+		return EndOf(tm, unit)
+	}
+	return StartOf(tm, unit)
+}
@@ -0,0 +1,93 @@
+package dat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUnit(t *testing.T) {
+	for _, u := range Units {
+		got, err := ParseUnit(string(u))
+		assert.NoError(t, err)
+		assert.Equal(t, u, got)
+	}
+	got, err := ParseUnit("Q")
+	assert.NoError(t, err)
+	assert.Equal(t, UnitQuarter, got)
+	got, err = ParseUnit("isoweek")
+	assert.NoError(t, err)
+	assert.Equal(t, UnitWeek, got)
+	_, err = ParseUnit("fortnight")
+	assert.EqualError(t, err, `unknown unit "fortnight"`)
+}
+
+func TestSnap(t *testing.T) {
+	// a thursday
+	tm := time.Date(2024, 5, 16, 14, 37, 42, 500, time.UTC)
+	tests := []struct {
+		unit           Unit
+		start, end, rd time.Time
+	}{
+		{UnitSecond,
+			time.Date(2024, 5, 16, 14, 37, 42, 0, time.UTC),
+			time.Date(2024, 5, 16, 14, 37, 42, 999999999, time.UTC),
+			time.Date(2024, 5, 16, 14, 37, 42, 0, time.UTC)},
+		{UnitMinute,
+			time.Date(2024, 5, 16, 14, 37, 0, 0, time.UTC),
+			time.Date(2024, 5, 16, 14, 37, 59, 999999999, time.UTC),
+			time.Date(2024, 5, 16, 14, 38, 0, 0, time.UTC)},
+		{UnitHour,
+			time.Date(2024, 5, 16, 14, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 16, 14, 59, 59, 999999999, time.UTC),
+			time.Date(2024, 5, 16, 15, 0, 0, 0, time.UTC)},
+		{UnitDay,
+			time.Date(2024, 5, 16, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 16, 23, 59, 59, 999999999, time.UTC),
+			time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC)},
+		{UnitWeek,
+			time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 19, 23, 59, 59, 999999999, time.UTC),
+			time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)},
+		{UnitMonth,
+			time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 31, 23, 59, 59, 999999999, time.UTC),
+			time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{UnitQuarter,
+			time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 6, 30, 23, 59, 59, 999999999, time.UTC),
+			time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{UnitYear,
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 12, 31, 23, 59, 59, 999999999, time.UTC),
+			time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		t.Run(string(test.unit), func(t *testing.T) {
+			assert.Equal(t, test.start, Snap(tm, SnapTruncate, test.unit))
+			assert.Equal(t, test.end, Snap(tm, SnapEnd, test.unit))
+			assert.Equal(t, test.rd, Snap(tm, SnapRound, test.unit))
+		})
+	}
+}
+
+func TestSnap_Zones(t *testing.T) {
+	la, err := time.LoadLocation(tzLosAngeles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the day of the switch to daylight saving is 23 hours long
+	tm := time.Date(2024, 3, 10, 12, 0, 0, 0, la)
+	start := StartOf(tm, UnitDay)
+	assert.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, la), start)
+	assert.Equal(t, 23*time.Hour, EndOf(tm, UnitDay).Add(time.Nanosecond).Sub(start))
+
+	// the repeated hour when daylight saving ends truncates within its offset
+	second := time.Date(2024, 11, 3, 9, 30, 0, 0, time.UTC).In(la)
+	assert.Equal(t, "01:00:00 PST", StartOf(second, UnitHour).Format("15:04:05 MST"))
+
+	// half hour offsets
+	kolkata := time.FixedZone("IST", 5*3600+1800)
+	assert.Equal(t, time.Date(2024, 5, 16, 14, 0, 0, 0, kolkata), StartOf(time.Date(2024, 5, 16, 14, 20, 0, 0, kolkata), UnitHour))
+}
@@ -0,0 +1,184 @@
+package dat
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Step is an amount of calendar and clock time between instants of a range.
+// Months and days follow the calendar of the location they are added in.
+type Step struct {
+	Months   int
+	Days     int
+	Duration time.Duration
+}
+
+// stepUnits are the calendar units of a step, clock units are those of time.ParseDuration
+var stepUnits = map[string]Step{
+	"y":  {Months: 12},
+	"q":  {Months: 3},
+	"mo": {Months: 1},
+	"w":  {Days: 7},
+	"d":  {Days: 1},
+}
+
+// ParseStep reads a step such as 1h, 15m, 1d, 2w, 1mo, 1q, 1y or 1d12h.
+// Units are y, q, mo, w and d, and the units of time.ParseDuration. A leading - steps backwards.
+func ParseStep(s string) (Step, error) {
+	var step Step
+	str := strings.TrimSpace(s)
+	sign := 1
+	if strings.HasPrefix(str, "-") {
+		sign, str = -1, str[1:]
+	}
+	if str == "" {
+		return step, fmt.Errorf("invalid step %q", s)
+	}
+	for str != "" {
+		digits := strings.IndexFunc(str, func(r rune) bool { return r < '0' || r > '9' })
+		if digits <= 0 {
+			return Step{}, fmt.Errorf("invalid step %q, expected a number at %q", s, str)
+		}
+		n, err := strconv.Atoi(str[:digits])
+		if err != nil {
+			return Step{}, fmt.Errorf("invalid step %q: %w", s, err)
+		}
+		str = str[digits:]
+		unitEnd := strings.IndexFunc(str, func(r rune) bool { return r >= '0' && r <= '9' })
+		if unitEnd < 0 {
+			unitEnd = len(str)
+		}
+		unit := str[:unitEnd]
+		str = str[unitEnd:]
+
+		if cal, ok := stepUnits[unit]; ok {
+			step.Months += sign * n * cal.Months
+			step.Days += sign * n * cal.Days
+			continue
+		}
+		d, err := time.ParseDuration(strconv.Itoa(n) + unit)
+		if err != nil {
+			return Step{}, fmt.Errorf("invalid step %q, unknown unit %q", s, unit)
+		}
+		step.Duration += time.Duration(sign) * d
+	}
+	if step.IsZero() {
+		return Step{}, fmt.Errorf("invalid step %q, steps must not be zero", s)
+	}
+	return step, nil
+}
+
+// IsZero reports whether the step does not move
+func (s Step) IsZero() bool {
+	return s.Months == 0 && s.Days == 0 && s.Duration == 0
+}
+
+// String implements fmt.Stringer
+func (s Step) String() string {
+	var b strings.Builder
+	if s.Months != 0 {
+		fmt.Fprintf(&b, "%dmo", s.Months)
+	}
+	if s.Days != 0 {
+		fmt.Fprintf(&b, "%dd", s.Days)
+	}
+	if s.Duration != 0 || b.Len() == 0 {
+		b.WriteString(s.Duration.String())
+	}
+	return b.String()
+}
+
+// Add returns tm moved n steps. Month steps keep the day of month, clamped to the
+// last day of shorter months, so Jan 31 plus 1mo is the end of February.
+func (s Step) Add(tm time.Time, n int) time.Time {
+	if s.Months != 0 {
+		tm = addMonths(tm, n*s.Months)
+	}
+	if s.Days != 0 {
+		tm = tm.AddDate(0, 0, n*s.Days)
+	}
+	return tm.Add(time.Duration(n) * s.Duration)
+}
+
+// addMonths moves tm by months, clamping the day to the length of the month
+func addMonths(tm time.Time, months int) time.Time {
+	year, month, day := tm.Date()
+	first := time.Date(year, month+time.Month(months), 1, tm.Hour(), tm.Minute(), tm.Second(), tm.Nanosecond(), tm.Location())
+	if last := daysIn(first); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// daysIn is the number of days in the month of tm
+func daysIn(tm time.Time) int {
+	return time.Date(tm.Year(), tm.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// forward reports whether the step moves forward in time
+func (s Step) forward() bool {
+	ref := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	return s.Add(ref, 1).After(ref)
+}
+
+// Align moves tm to the nearest boundary of the step's largest unit in the step's direction,
+// the start of a year, quarter, month, ISO week or day, or a multiple of the duration
+// on the wall clock of tm's location.
+func (s Step) Align(tm time.Time) time.Time {
+	var floor time.Time
+	var unit Step
+	switch {
+	case s.Months%12 == 0 && s.Months != 0:
+		floor, unit = StartOf(tm, UnitYear), Step{Months: 12}
+	case s.Months%3 == 0 && s.Months != 0:
+		floor, unit = StartOf(tm, UnitQuarter), Step{Months: 3}
+	case s.Months != 0:
+		floor, unit = StartOf(tm, UnitMonth), Step{Months: 1}
+	case s.Days%7 == 0 && s.Days != 0:
+		floor, unit = StartOf(tm, UnitWeek), Step{Days: 7}
+	case s.Days != 0:
+		floor, unit = StartOf(tm, UnitDay), Step{Days: 1}
+	default:
+		d := s.Duration
+		if d < 0 {
+			d = -d
+		}
+		floor, unit = truncateWall(tm, d), Step{Duration: d}
+	}
+	if s.forward() && floor.Before(tm) {
+		return unit.Add(floor, 1)
+	}
+	return floor
+}
+
+// ErrStepDirection the step moves away from the end of a range
+var ErrStepDirection = errors.New("step moves away from the end of the range")
+
+// Range calls fn with each instant from start to end, separated by step, stopping at the
+// first error. The end is included when it falls on a step unless exclusive is set.
+// Steps are added in the location of start.
+func Range(start, end time.Time, step Step, exclusive bool, fn func(time.Time) error) error {
+	if step.IsZero() {
+		return errors.New("steps must not be zero")
+	}
+	forward := step.forward()
+	if (forward && end.Before(start)) || (!forward && end.After(start)) {
+		return ErrStepDirection
+	}
+	for i := 0; ; i++ {
+		tm := step.Add(start, i)
+		past := tm.After(end)
+		if !forward {
+			past = tm.Before(end)
+		}
+		if past || (exclusive && tm.Equal(end)) {
+			return nil
+		}
+		if err := fn(tm); err != nil {
+			return err
+		}
+	}
+}
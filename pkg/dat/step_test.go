@@ -0,0 +1,133 @@
+package dat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStep(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Step
+		error bool
+	}{
+		{"1h", Step{Duration: time.Hour}, false},
+		{"15m", Step{Duration: 15 * time.Minute}, false},
+		{"1d12h", Step{Days: 1, Duration: 12 * time.Hour}, false},
+		{"2w", Step{Days: 14}, false},
+		{"1mo", Step{Months: 1}, false},
+		{"1q", Step{Months: 3}, false},
+		{"1y6mo", Step{Months: 18}, false},
+		{"-1d", Step{Days: -1}, false},
+		{"500ms", Step{Duration: 500 * time.Millisecond}, false},
+		{"0d", Step{}, true},
+		{"", Step{}, true},
+		{"d", Step{}, true},
+		{"1fortnight", Step{}, true},
+		{"1.5h", Step{}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			got, err := ParseStep(test.input)
+			if test.error {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestStep_String(t *testing.T) {
+	assert.Equal(t, "1h0m0s", Step{Duration: time.Hour}.String())
+	assert.Equal(t, "1mo2d3h0m0s", Step{Months: 1, Days: 2, Duration: 3 * time.Hour}.String())
+	assert.Equal(t, "0s", Step{}.String())
+}
+
+func TestStep_Add(t *testing.T) {
+	jan31 := time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC)
+	month := Step{Months: 1}
+	assert.Equal(t, time.Date(2024, 2, 29, 10, 0, 0, 0, time.UTC), month.Add(jan31, 1))
+	assert.Equal(t, time.Date(2024, 3, 31, 10, 0, 0, 0, time.UTC), month.Add(jan31, 2))
+	assert.Equal(t, time.Date(2023, 12, 31, 10, 0, 0, 0, time.UTC), month.Add(jan31, -1))
+
+	// days keep the wall clock across daylight saving
+	la, err := time.LoadLocation(tzLosAngeles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := time.Date(2024, 3, 9, 0, 0, 0, 0, la)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, la), Step{Days: 1}.Add(before, 2))
+	assert.Equal(t, 47*time.Hour, Step{Days: 1}.Add(before, 2).Sub(before))
+}
+
+func TestStep_Align(t *testing.T) {
+	tm := time.Date(2024, 5, 15, 10, 37, 12, 0, time.UTC)
+	tests := []struct {
+		step string
+		want time.Time
+	}{
+		{"15m", time.Date(2024, 5, 15, 10, 45, 0, 0, time.UTC)},
+		{"1h", time.Date(2024, 5, 15, 11, 0, 0, 0, time.UTC)},
+		{"-1h", time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)},
+		{"1d", time.Date(2024, 5, 16, 0, 0, 0, 0, time.UTC)},
+		{"1w", time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)},
+		{"1mo", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"1q", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"-1q", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"1y", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		t.Run(test.step, func(t *testing.T) {
+			step, err := ParseStep(test.step)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, step.Align(tm))
+		})
+	}
+
+	// aligned times stay put
+	hour := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, hour, Step{Duration: time.Hour}.Align(hour))
+
+	// durations align to the wall clock of the location
+	kolkata := time.FixedZone("IST", 5*3600+1800)
+	assert.Equal(t, time.Date(2024, 5, 15, 11, 0, 0, 0, kolkata), Step{Duration: time.Hour}.Align(time.Date(2024, 5, 15, 10, 10, 0, 0, kolkata)))
+}
+
+func TestRange(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(3 * time.Hour)
+	collect := func(start, end time.Time, step Step, exclusive bool) ([]time.Time, error) {
+		var got []time.Time
+		err := Range(start, end, step, exclusive, func(tm time.Time) error {
+			got = append(got, tm)
+			return nil
+		})
+		return got, err
+	}
+
+	got, err := collect(start, end, Step{Duration: time.Hour}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{start, start.Add(time.Hour), start.Add(2 * time.Hour), end}, got)
+
+	got, err = collect(start, end, Step{Duration: time.Hour}, true)
+	assert.NoError(t, err)
+	assert.Len(t, got, 3)
+
+	got, err = collect(end, start, Step{Duration: -time.Hour}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{end, end.Add(-time.Hour), end.Add(-2 * time.Hour)}, got)
+
+	got, err = collect(start, end, Step{Duration: 2 * time.Hour}, false)
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	_, err = collect(end, start, Step{Duration: time.Hour}, false)
+	assert.ErrorIs(t, err, ErrStepDirection)
+
+	err = Range(start, end, Step{Duration: time.Hour}, false, func(time.Time) error { return assert.AnError })
+	assert.ErrorIs(t, err, assert.AnError)
+}
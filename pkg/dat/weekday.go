@@ -0,0 +1,21 @@
+package dat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseWeekday returns the weekday with the given english name, case insensitive.
+// Any prefix of at least two letters is accepted, mo, mon and monday are all monday.
+func ParseWeekday(name string) (time.Weekday, error) {
+	lower := strings.ToLower(strings.TrimSpace(name))
+	if len(lower) >= 2 {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.HasPrefix(strings.ToLower(d.String()), lower) {
+				return d, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unknown weekday %q", name)
+}
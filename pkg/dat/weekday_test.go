@@ -0,0 +1,35 @@
+package dat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWeekday(t *testing.T) {
+	tests := []struct {
+		name  string
+		want  time.Weekday
+		error bool
+	}{
+		{"monday", time.Monday, false},
+		{"Sun", time.Sunday, false},
+		{"TU", time.Tuesday, false},
+		{"th", time.Thursday, false},
+		{"s", 0, true},
+		{"moonday", 0, true},
+		{"", 0, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseWeekday(test.name)
+			if test.error {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
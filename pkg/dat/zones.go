@@ -0,0 +1,239 @@
+package dat
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// zoneDirs are the usual locations of the tz database
+var zoneDirs = []string{
+	"/usr/share/zoneinfo/",
+	"/usr/share/lib/zoneinfo/",
+	"/usr/lib/locale/TZ/",
+}
+
+var (
+	zonesOnce sync.Once
+	zones     []string
+
+	locationsOnce sync.Once
+	locations     []*time.Location
+)
+
+// Zones returns the tz database zone names available on this system, sorted.
+// The ZONEINFO environment variable is honored like time.LoadLocation does.
+func Zones() []string {
+	zonesOnce.Do(func() {
+		zones = loadZones()
+	})
+	return zones
+}
+
+func loadZones() []string {
+	if zi := os.Getenv("ZONEINFO"); zi != "" {
+		if names := zipZones(zi); len(names) > 0 {
+			return names
+		}
+		if names := dirZones(zi); len(names) > 0 {
+			return names
+		}
+	}
+	for _, dir := range zoneDirs {
+		if names := dirZones(dir); len(names) > 0 {
+			return names
+		}
+	}
+	return nil
+}
+
+// isZoneName filters out the helper files that live alongside zone files
+func isZoneName(name string) bool {
+	if name == "" || name[0] < 'A' || name[0] > 'Z' {
+		return false
+	}
+	return !strings.HasPrefix(name, "posix/") && !strings.HasPrefix(name, "right/") &&
+		!strings.Contains(name, ".")
+}
+
+func dirZones(dir string) []string {
+	var names []string
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil
+		}
+		name = filepath.ToSlash(name)
+		if isZoneName(name) && isTZif(path) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	sort.Strings(names)
+	return names
+}
+
+func zipZones(path string) []string {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil
+	}
+	defer zr.Close()
+
+	var names []string
+	for _, f := range zr.File {
+		if isZoneName(f.Name) && !strings.HasSuffix(f.Name, "/") {
+			names = append(names, f.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isTZif checks the magic header of a zone file
+func isTZif(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(f, magic); err != nil {
+		return false
+	}
+	return string(magic) == "TZif"
+}
+
+// offsetPattern matches fixed offsets such as +05:30, -0800, UTC-8 or GMT+2
+var offsetPattern = regexp.MustCompile(`^(?i:utc|gmt)?([+-])(\d{1,2})(?::?(\d{2}))?$`)
+
+// posixTZPattern matches POSIX TZ strings such as EST5EDT,M3.2.0,M11.1.0 or <+0330>-3:30
+var posixTZPattern = func() *regexp.Regexp {
+	name := `(?:[A-Za-z]{3,}|<[A-Za-z0-9+-]{3,}>)`
+	offset := `[+-]?\d{1,2}(?::\d{2}(?::\d{2})?)?`
+	date := `(?:J\d{1,3}|\d{1,3}|M(?:[1-9]|1[0-2])\.[1-5]\.[0-6])`
+	at := `(?:/[+-]?\d{1,3}(?::\d{2}(?::\d{2})?)?)?`
+	return regexp.MustCompile(`^` + name + offset + `(?:` + name + `(?:` + offset + `)?(?:,` + date + at + `,` + date + at + `)?)?$`)
+}()
+
+// parseOffset is the offset in seconds east of utc of a fixed offset zone,
+// ok is false when the name is not written as an offset
+func parseOffset(name string) (offset int, ok bool, err error) {
+	if strings.EqualFold(name, "Z") {
+		return 0, true, nil
+	}
+	m := offsetPattern.FindStringSubmatch(name)
+	if m == nil {
+		return 0, false, nil
+	}
+	hours, _ := strconv.Atoi(m[2])
+	minutes, _ := strconv.Atoi(m[3])
+	if hours > 24 || minutes > 59 {
+		return 0, true, fmt.Errorf("offset %q is out of range", name)
+	}
+	offset = hours*3600 + minutes*60
+	if m[1] == "-" {
+		offset = -offset
+	}
+	return offset, true, nil
+}
+
+// ZoneOffset returns the offset in seconds east of UTC of a zone written as a fixed offset,
+// such as Z, +05:30, -0800, UTC-8 or GMT+2. It is false for any other zone.
+func ZoneOffset(name string) (int, bool) {
+	offset, ok, err := parseOffset(strings.TrimSpace(name))
+	return offset, ok && err == nil
+}
+
+// LoadZone returns the location of a fixed offset such as +05:30, UTC-8 or Z, a tz database name,
+// or a POSIX TZ string such as EST5EDT,M3.2.0,M11.1.0. Fixed offsets are east of UTC, as in
+// ISO 8601, while the offsets of POSIX TZ strings are west of UTC, as in the TZ variable.
+// Fixed offsets are named by their offset from UTC, such as UTC+05:30, which LoadZone reads back.
+func LoadZone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if offset, ok, err := parseOffset(name); ok {
+		if err != nil {
+			return nil, err
+		}
+		if offset == 0 {
+			return time.UTC, nil
+		}
+		return time.FixedZone("UTC"+formatOffset(offset), offset), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err == nil {
+		return loc, nil
+	}
+	if posixTZPattern.MatchString(name) {
+		return time.LoadLocationFromTZData(name, posixTZData(name))
+	}
+	return nil, err
+}
+
+// formatOffset writes an offset in seconds as ±hh:mm
+func formatOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, offset/3600, offset/60%60)
+}
+
+// posixTZData is a TZif file with no transitions whose footer is the TZ string,
+// so the time package applies its rules to every instant
+func posixTZData(tz string) []byte {
+	var b bytes.Buffer
+	header := func(types, chars uint32) {
+		b.WriteString("TZif2")
+		b.Write(make([]byte, 15))
+		// utc/local and standard/wall indicators, leap seconds, transitions, types and characters
+		for _, n := range []uint32{0, 0, 0, 0, types, chars} {
+			_ = binary.Write(&b, binary.BigEndian, n)
+		}
+	}
+	// an empty version 1 block, then a single utc type that the footer overrides
+	header(0, 0)
+	header(1, 4)
+	b.Write([]byte{0, 0, 0, 0, 0, 0})
+	b.WriteString("UTC\x00")
+	b.WriteString("\n" + tz + "\n")
+	return b.Bytes()
+}
+
+// zoneLocations returns the locations of Zones that load, loaded once
+func zoneLocations() []*time.Location {
+	locationsOnce.Do(func() {
+		for _, name := range Zones() {
+			if loc, err := time.LoadLocation(name); err == nil {
+				locations = append(locations, loc)
+			}
+		}
+	})
+	return locations
+}
+
+// ZonesAtOffset returns the tz database zones whose offset from UTC at tm is offset,
+// in seconds east of UTC.
+func ZonesAtOffset(offset int, tm time.Time) []string {
+	var names []string
+	for _, loc := range zoneLocations() {
+		if _, off := tm.In(loc).Zone(); off == offset {
+			names = append(names, loc.String())
+		}
+	}
+	return names
+}
@@ -0,0 +1,98 @@
+package dat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZones(t *testing.T) {
+	zones := Zones()
+	if len(zones) == 0 {
+		t.Skip("no tz database available")
+	}
+	assert.Contains(t, zones, tzLosAngeles)
+	assert.NotContains(t, zones, "zone.tab")
+	assert.IsIncreasing(t, zones)
+	for _, zone := range zones[:10] {
+		_, err := time.LoadLocation(zone)
+		assert.NoError(t, err, zone)
+	}
+}
+
+func TestIsZoneName(t *testing.T) {
+	assert.True(t, isZoneName("America/Los_Angeles"))
+	assert.True(t, isZoneName("UTC"))
+	assert.False(t, isZoneName("posixrules"))
+	assert.False(t, isZoneName("zone1970.tab"))
+	assert.False(t, isZoneName("right/UTC"))
+	assert.False(t, isZoneName(""))
+}
+
+func TestLoadZone(t *testing.T) {
+	summer := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	winter := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		zone   string
+		name   string
+		summer string
+		winter string
+		error  bool
+	}{
+		{"+05:30", "UTC+05:30", "+05:30", "+05:30", false},
+		{"+0530", "UTC+05:30", "+05:30", "+05:30", false},
+		{"UTC-8", "UTC-08:00", "-08:00", "-08:00", false},
+		{"gmt+2", "UTC+02:00", "+02:00", "+02:00", false},
+		{"Z", "UTC", "Z", "Z", false},
+		{"-00:00", "UTC", "Z", "Z", false},
+		{"UTC+05:30", "UTC+05:30", "+05:30", "+05:30", false},
+		{tzLosAngeles, tzLosAngeles, "-07:00", "-08:00", false},
+		{"EST5EDT,M3.2.0,M11.1.0", "EST5EDT,M3.2.0,M11.1.0", "-04:00", "-05:00", false},
+		{"CET-1CEST,M3.5.0,M10.5.0/3", "CET-1CEST,M3.5.0,M10.5.0/3", "+02:00", "+01:00", false},
+		{"<+0330>-3:30", "<+0330>-3:30", "+03:30", "+03:30", false},
+		{"+25:00", "", "", "", true},
+		{"+05:75", "", "", "", true},
+		{"EST5EDT,M13.2.0,M11.1.0", "", "", "", true},
+		{"Mars/Olympus", "", "", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.zone, func(t *testing.T) {
+			loc, err := LoadZone(test.zone)
+			if test.error {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, test.name, loc.String())
+			assert.Equal(t, test.summer, summer.In(loc).Format("Z07:00"))
+			assert.Equal(t, test.winter, winter.In(loc).Format("Z07:00"))
+		})
+	}
+}
+
+func TestZoneOffset(t *testing.T) {
+	offset, ok := ZoneOffset("+05:30")
+	assert.True(t, ok)
+	assert.Equal(t, 19800, offset)
+	offset, ok = ZoneOffset("UTC-8")
+	assert.True(t, ok)
+	assert.Equal(t, -28800, offset)
+	_, ok = ZoneOffset("+25:00")
+	assert.False(t, ok)
+	_, ok = ZoneOffset(tzLosAngeles)
+	assert.False(t, ok)
+	_, ok = ZoneOffset("UTC")
+	assert.False(t, ok)
+}
+
+func TestZonesAtOffset(t *testing.T) {
+	if len(Zones()) == 0 {
+		t.Skip("no tz database available")
+	}
+	assert.Contains(t, ZonesAtOffset(19800, time.Now()), "Asia/Kolkata")
+	assert.Contains(t, ZonesAtOffset(-7*3600, time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)), tzLosAngeles)
+	assert.NotContains(t, ZonesAtOffset(-7*3600, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)), tzLosAngeles)
+}
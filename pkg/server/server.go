@@ -0,0 +1,436 @@
+// Package server exposes the dat conversion engine over http.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Setheck/dat/pkg/dat"
+)
+
+// DefaultTimeout bounds the time spent on a single request
+const DefaultTimeout = 10 * time.Second
+
+// maxBatchBytes limits the size of a batch request body
+const maxBatchBytes = 1 << 20
+
+// test points
+var timeNow = time.Now
+
+// Server is an http server for the conversion engine
+type Server struct {
+	// Addr is the address to listen on, see http.Server
+	Addr string
+	// Timeout bounds each request, DefaultTimeout when zero
+	Timeout time.Duration
+}
+
+// ListenAndServe serves until the context is done, then shuts down gracefully,
+// giving in flight requests up to Timeout to finish.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	srv := &http.Server{
+		Addr:              s.Addr,
+		Handler:           http.TimeoutHandler(NewHandler(), timeout, `{"error":"request timed out"}`),
+		ReadHeaderTimeout: timeout,
+		ReadTimeout:       timeout,
+		WriteTimeout:      2 * timeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NewHandler returns the http handler serving
+//
+//	GET  /convert?value=...  convert a single value
+//	POST /batch              convert a json array of values
+//	GET  /now                the current time
+//	GET  /zones              the available tz database zone names
+//
+// convert, batch and now accept the query parameters zone, format, dialect, locale, delta, ms, tf,
+// id, snowflake, minYear, maxYear, epochBase, outputBase, scale, outputScale, base, bytes, truncate,
+// round, startOf, endOf, calendar, strict, epochs and all matching the command line flags. base is
+// the radix, bytes is the byte order of raw byte input, big or little, and calendar can be repeated
+// or comma separated.
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/convert", handleConvert)
+	mux.HandleFunc("/batch", handleBatch)
+	mux.HandleFunc("/now", handleNow)
+	mux.HandleFunc("/zones", handleZones)
+	return mux
+}
+
+// errorBody is the json body of a failed conversion
+type errorBody struct {
+	Error       string   `json:"error"`
+	Kind        string   `json:"kind,omitempty"`
+	Position    *int     `json:"position,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// batchItem is the result of one value in a batch
+type batchItem struct {
+	Value  string      `json:"value"`
+	Result *dat.Result `json:"result,omitempty"`
+	Error  *errorBody  `json:"error,omitempty"`
+}
+
+func handleConvert(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	conv, err := converter(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, newErrorBody(err))
+		return
+	}
+	value := r.URL.Query().Get("value")
+	if value == "" {
+		writeJSON(w, http.StatusBadRequest, errorBody{Error: "missing value"})
+		return
+	}
+	res, err := conv.Convert(value)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, newErrorBody(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func handleBatch(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	conv, err := converter(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, newErrorBody(err))
+		return
+	}
+
+	var values []string
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBytes)).Decode(&values); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorBody{Error: "body must be a json array of strings: " + err.Error()})
+		return
+	}
+
+	items := make([]batchItem, 0, len(values))
+	for _, value := range values {
+		item := batchItem{Value: value}
+		if res, err := conv.Convert(value); err != nil {
+			body := newErrorBody(err)
+			item.Error = &body
+		} else {
+			item.Result = res
+		}
+		items = append(items, item)
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func handleNow(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	conv, err := converter(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, newErrorBody(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, conv.At(timeNow()))
+}
+
+func handleZones(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	zones := dat.Zones()
+	if zones == nil {
+		zones = []string{}
+	}
+	writeJSON(w, http.StatusOK, zones)
+}
+
+// converter creates a dat.Converter from the request query parameters
+func converter(r *http.Request) (*dat.Converter, error) {
+	q := r.URL.Query()
+	opts := []dat.Option{
+		dat.WithFormat(q.Get("format")),
+		dat.WithDelta(q.Get("delta")),
+		dat.WithZone(q.Get("zone")),
+	}
+	if err := checkStrict(q); err != nil {
+		return nil, err
+	}
+	if v := q.Get("dialect"); v != "" {
+		dialect, err := dat.ParseDialect(v)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, dat.WithDialect(dialect))
+	}
+	if v := q.Get("locale"); v != "" {
+		locale, err := dat.LookupLocale(v)
+		if err != nil {
+			return nil, err
+		}
+		if locale != nil {
+			opts = append(opts, dat.WithLocale(locale))
+		}
+	}
+	if v := q.Get("id"); v != "" {
+		kind := dat.IDKind(strings.ToLower(v))
+		if !containsKind(dat.IDKinds, kind) {
+			return nil, fmt.Errorf("unknown id kind %q", v)
+		}
+		opts = append(opts, dat.WithID(kind))
+	}
+	if v := q.Get("snowflake"); v != "" {
+		ms, err := dat.ParseSnowflakeEpoch(v)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, dat.WithSnowflakeEpoch(ms))
+	}
+	for param, option := range map[string]func(int) dat.Option{
+		"minYear": dat.WithMinYear,
+		"maxYear": dat.WithMaxYear,
+	} {
+		if v := q.Get(param); v != "" {
+			year, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s parameter: %s", param, v)
+			}
+			opts = append(opts, option(year))
+		}
+	}
+	if cals, err := dat.ParseCalendars(q["calendar"]); err != nil {
+		return nil, err
+	} else if len(cals) > 0 {
+		opts = append(opts, dat.WithCalendars(cals...))
+	}
+	if ms := q.Get("ms"); ms != "" {
+		b, err := strconv.ParseBool(ms)
+		if err != nil {
+			return nil, errors.New("invalid ms parameter: " + ms)
+		}
+		if b {
+			opts = append(opts, dat.WithPrecision(dat.Milliseconds))
+		}
+	}
+	if epochs := q.Get("epochs"); epochs != "" {
+		b, err := strconv.ParseBool(epochs)
+		if err != nil {
+			return nil, errors.New("invalid epochs parameter: " + epochs)
+		}
+		opts = append(opts, dat.WithEpochs(b))
+	}
+	if all := q.Get("all"); all != "" {
+		b, err := strconv.ParseBool(all)
+		if err != nil {
+			return nil, errors.New("invalid all parameter: " + all)
+		}
+		opts = append(opts, dat.WithBases(b), dat.WithMetadata(b), dat.WithOffsetZones(b))
+	}
+	if tf := q.Get("tf"); tf != "" {
+		b, err := strconv.ParseBool(tf)
+		if err != nil {
+			return nil, errors.New("invalid tf parameter: " + tf)
+		}
+		opts = append(opts, dat.WithTimeFormats(b))
+	}
+	for param, option := range map[string]func(dat.EpochBase) dat.Option{
+		"epochBase":  dat.WithEpochBase,
+		"outputBase": dat.WithOutputBase,
+	} {
+		if v := q.Get(param); v != "" {
+			base, err := dat.ParseEpochBase(v)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, option(base))
+		}
+	}
+	for param, option := range map[string]func(dat.Scale) dat.Option{
+		"scale":       dat.WithScale,
+		"outputScale": dat.WithOutputScale,
+	} {
+		if v := q.Get(param); v != "" {
+			scale, err := dat.ParseScale(v)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, option(scale))
+		}
+	}
+	if v := q.Get("base"); v != "" {
+		radix, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, errors.New("invalid base parameter: " + v)
+		}
+		if err := dat.ValidRadix(radix); err != nil {
+			return nil, err
+		}
+		opts = append(opts, dat.WithRadix(radix))
+	}
+	if v := q.Get("bytes"); v != "" {
+		order, err := dat.ParseByteOrder(v)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, dat.WithBytes(order))
+	}
+	snapped := false
+	for _, s := range []struct {
+		param string
+		mode  dat.SnapMode
+	}{
+		{"truncate", dat.SnapTruncate},
+		{"round", dat.SnapRound},
+		{"startOf", dat.SnapTruncate},
+		{"endOf", dat.SnapEnd},
+	} {
+		v := q.Get(s.param)
+		if v == "" {
+			continue
+		}
+		if snapped {
+			return nil, errors.New("only one of truncate, round, startOf and endOf can be set")
+		}
+		unit, err := dat.ParseUnit(v)
+		if err != nil {
+			return nil, err
+		}
+		opts, snapped = append(opts, dat.WithSnap(s.mode, unit)), true
+	}
+	return dat.NewConverter(opts...), nil
+}
+
+// checkStrict fails, when strict is set, for the format, delta and zone the converter would
+// otherwise ignore, like --strict does
+func checkStrict(q url.Values) error {
+	if v := q.Get("strict"); v == "" {
+		return nil
+	} else if strict, err := strconv.ParseBool(v); err != nil {
+		return errors.New("invalid strict parameter: " + v)
+	} else if !strict {
+		return nil
+	}
+	if format := q.Get("format"); format != "" {
+		dialect, err := dat.ParseDialect(q.Get("dialect"))
+		if err != nil {
+			return err
+		}
+		layout, err := dat.TranslateLayout(format, dialect)
+		if err != nil {
+			return err
+		}
+		if err := dat.ValidateLayout(layout); err != nil {
+			return err
+		}
+	}
+	if delta := q.Get("delta"); delta != "" {
+		if _, business := dat.ParseBusinessDelta(delta); !business {
+			if _, err := time.ParseDuration(delta); err != nil {
+				return fmt.Errorf("invalid delta: %w", err)
+			}
+		}
+	}
+	if zone := q.Get("zone"); zone != "" {
+		if _, err := dat.LoadZone(zone); err != nil {
+			return fmt.Errorf("invalid zone: %w", err)
+		}
+	}
+	return nil
+}
+
+// containsKind reports whether kind is one of kinds
+func containsKind(kinds []dat.IDKind, kind dat.IDKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// errorKinds name the dat errors for clients
+var errorKinds = []struct {
+	err  error
+	kind string
+}{
+	{dat.ErrNotAnEpoch, "not_an_epoch"},
+	{dat.ErrOutOfRange, "out_of_range"},
+	{dat.ErrUnknownFormat, "unknown_format"},
+	{dat.ErrAmbiguous, "ambiguous"},
+	{dat.ErrNotAnID, "not_an_id"},
+	{dat.ErrInvalidLayout, "invalid_layout"},
+	{dat.ErrNoFireTime, "no_fire_time"},
+	{dat.ErrStepDirection, "step_direction"},
+}
+
+func newErrorBody(err error) errorBody {
+	body := errorBody{Error: err.Error()}
+	for _, k := range errorKinds {
+		if errors.Is(err, k.err) {
+			body.Kind = k.kind
+			break
+		}
+	}
+	var pe *dat.ParseError
+	if errors.As(err, &pe) {
+		if pe.Pos >= 0 {
+			pos := pe.Pos
+			body.Position = &pos
+		}
+		body.Suggestions = pe.Suggestions
+	}
+	return body
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method || (method == http.MethodGet && r.Method == http.MethodHead) {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeJSON(w, http.StatusMethodNotAllowed, errorBody{Error: "method not allowed"})
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	// allow dashboards and bookmarklets on other origins
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Setheck/dat/pkg/dat"
+)
+
+func TestHandler_Convert(t *testing.T) {
+	tm := time.Unix(1601167426, 0)
+	tests := []struct {
+		name   string
+		target string
+		status int
+		want   string
+	}{
+		{"epoch", "/convert?value=1601167426", http.StatusOK, tm.UTC().Format(dat.DateFormat)},
+		{"format and zone", "/convert?value=1601167426&zone=UTC&format=rfc3339", http.StatusOK, tm.UTC().Format(time.RFC3339)},
+		{"milliseconds", "/convert?value=1601167426000&ms=true", http.StatusOK, tm.UTC().Format(dat.DateFormat)},
+		{"epoch base", "/convert?value=3810156226&epochBase=ntp&outputBase=mac", http.StatusOK, `"baseValue":"622860226"`},
+		{"bad epoch base", "/convert?value=1&epochBase=lotus", http.StatusBadRequest, "unknown epoch base"},
+		{"time scales", "/convert?value=1601167463&scale=tai&outputScale=gps", http.StatusOK, `"epoch":1601167444`},
+		{"radix", "/convert?value=5f6fe042&base=16", http.StatusOK, `"radixValue":"0x5f6fe042"`},
+		{"bad radix", "/convert?value=1&base=40", http.StatusBadRequest, "invalid base 40"},
+		{"epoch base is not a radix", "/convert?value=1&base=ntp", http.StatusBadRequest, "invalid base parameter: ntp"},
+		{"bytes", "/convert?value=42e06f5f&bytes=little", http.StatusOK, `"epoch":1601167426`},
+		{"end of day", "/convert?value=1601167426&endOf=day&zone=UTC", http.StatusOK, `"epoch":1601251199`},
+		{"two snaps", "/convert?value=1&round=day&truncate=hour", http.StatusBadRequest, "only one of"},
+		{"bad unit", "/convert?value=1&round=fortnight", http.StatusBadRequest, "unknown unit"},
+		{"bad scale", "/convert?value=1&scale=tt", http.StatusBadRequest, "unknown time scale"},
+		{"missing value", "/convert", http.StatusBadRequest, "missing value"},
+		{"bad epoch", "/convert?value=12ab", http.StatusBadRequest, "not_an_epoch"},
+		{"bad ms", "/convert?value=1&ms=maybe", http.StatusBadRequest, "invalid ms parameter"},
+		{"id", "/convert?value=01ARZ3NDEKTSV4RRFFQ69G5FAV&id=ulid", http.StatusOK, `"kind":"ulid"`},
+		{"not an id", "/convert?value=1601167426&id=ulid", http.StatusBadRequest, "not_an_id"},
+		{"bad id kind", "/convert?value=1&id=guid", http.StatusBadRequest, "unknown id kind"},
+		{"snowflake epoch", "/convert?value=175928847299117063&id=snowflake&snowflake=discord", http.StatusOK, `"epoch":1462015105`},
+		{"bad snowflake epoch", "/convert?value=1&snowflake=myspace", http.StatusBadRequest, "snowflake"},
+		{"min year", "/convert?value=1&minYear=2000", http.StatusBadRequest, "out_of_range"},
+		{"max year", "/convert?value=1601167426&maxYear=2030", http.StatusOK, `"epoch":1601167426`},
+		{"bad min year", "/convert?value=1&minYear=soon", http.StatusBadRequest, "invalid minYear parameter"},
+		{"dialect", "/convert?value=1601167426&zone=UTC&format=%25Y-%25m-%25d&dialect=strftime", http.StatusOK, `"formatted":"2020-09-27"`},
+		{"bad dialect", "/convert?value=1&dialect=klingon", http.StatusBadRequest, "unknown format dialect"},
+		{"locale", "/convert?value=1601167426&zone=UTC&format=Monday&locale=fr", http.StatusOK, `"formatted":"dimanche"`},
+		{"bad locale", "/convert?value=1&locale=klingon", http.StatusBadRequest, "unknown locale"},
+		{"calendars", "/convert?value=1601167426&zone=UTC&calendar=hebrew,iso&calendar=japanese", http.StatusOK, `"text":"Reiwa 2-09-27 (令和2年9月27日)"`},
+		{"bad calendar", "/convert?value=1&calendar=mayan", http.StatusBadRequest, "unknown calendar"},
+		{"strict layout", "/convert?value=1&format=nothing&strict=true", http.StatusBadRequest, "invalid_layout"},
+		{"strict dialect", "/convert?value=1&format=%25Q&dialect=strftime&strict=true", http.StatusBadRequest, "invalid_layout"},
+		{"strict zone", "/convert?value=1&zone=Mars/Olympus&strict=true", http.StatusBadRequest, "invalid zone"},
+		{"all", "/convert?value=0&all=true", http.StatusOK, `"bases":[{"base":"unix","value":"0"}`},
+		{"all metadata", "/convert?value=0&all=true&zone=UTC", http.StatusOK, `"zone":{"weekday":"Thursday","isoYear":1970,"isoWeek":1,"dayOfYear":1`},
+		{"epochs", "/convert?value=1700000000&epochs=true", http.StatusOK, `"epochs":[{"unit":"s","value":"1700000000","hex":"0x6553f100"}`},
+		{"epochs in bases", "/convert?value=1700000000&epochs=true&outputBase=ntp", http.StatusOK, `"epochBases":[{"base":"ntp","value":"3908988800"}]`},
+		{"bad epochs", "/convert?value=0&epochs=some", http.StatusBadRequest, "invalid epochs parameter"},
+		{"all offset zones", "/convert?value=0&all=true&zone=%2B05:30", http.StatusOK, `"offsetZones":["Asia/`},
+		{"bad all", "/convert?value=0&all=every", http.StatusBadRequest, "invalid all parameter"},
+		{"not strict", "/convert?value=1&format=nothing&zone=Mars/Olympus", http.StatusOK, `"epoch":1`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			NewHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.target, nil))
+			assert.Equal(t, test.status, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.Contains(t, rec.Body.String(), test.want)
+		})
+	}
+}
+
+func TestHandler_ConvertError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/convert?value=12ab", nil))
+
+	var body errorBody
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, "not_an_epoch", body.Kind)
+	if assert.NotNil(t, body.Position) {
+		assert.Equal(t, 2, *body.Position)
+	}
+}
+
+func TestNewErrorBody(t *testing.T) {
+	for _, k := range errorKinds {
+		body := newErrorBody(&dat.ParseError{Input: "x", Pos: -1, Err: k.err})
+		assert.Equal(t, k.kind, body.Kind)
+	}
+	assert.Empty(t, newErrorBody(errors.New("other")).Kind)
+}
+
+func TestHandler_Batch(t *testing.T) {
+	rec := httptest.NewRecorder()
+	body := strings.NewReader(`["1601167426", "nope"]`)
+	NewHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/batch?zone=UTC", body))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var items []batchItem
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&items))
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, "1601167426", items[0].Value)
+		if assert.NotNil(t, items[0].Result) {
+			assert.Equal(t, int64(1601167426), items[0].Result.Epoch)
+			assert.Equal(t, "UTC", items[0].Result.ZoneName)
+		}
+		assert.Nil(t, items[0].Error)
+		assert.Nil(t, items[1].Result)
+		if assert.NotNil(t, items[1].Error) {
+			assert.Equal(t, "not_an_epoch", items[1].Error.Kind)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	NewHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	NewHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/batch", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, http.MethodPost, rec.Header().Get("Allow"))
+}
+
+func TestHandler_Now(t *testing.T) {
+	saveTimeNow := timeNow
+	defer func() { timeNow = saveTimeNow }()
+	timeNow = func() time.Time {
+		return time.Unix(1601167426, 0)
+	}
+
+	rec := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/now?ms=1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var res dat.Result
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+	assert.Equal(t, int64(1601167426000), res.Epoch)
+}
+
+func TestHandler_Zones(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/zones", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var zones []string
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&zones))
+	assert.ElementsMatch(t, dat.Zones(), zones)
+}
+
+func TestServer_ListenAndServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := &Server{Addr: "127.0.0.1:0", Timeout: time.Second}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.ListenAndServe(ctx)
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+}